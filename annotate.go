@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"html"
+	"strings"
+)
+
+// Marker decides how Annotate highlights matches.
+type Marker struct {
+	// Wrap returns the annotated form of a match, given the text it
+	// covers, already escaped.
+	Wrap func(m Match, text string) string
+	// Escape, when set, is applied to all of the input text, such as
+	// html.EscapeString for HTML output.
+	Escape func(text string) string
+}
+
+// ansiColors are the terminal colors of ANSIMarker by kind; other kinds
+// are green.
+var ansiColors = map[string]string{
+	"url":    "\x1b[1;31m",
+	"domain": "\x1b[31m",
+	"ipv4":   "\x1b[35m",
+	"ipv6":   "\x1b[35m",
+	"ipport": "\x1b[35m",
+	"email":  "\x1b[36m",
+	"md5":    "\x1b[33m",
+	"sha1":   "\x1b[33m",
+	"sha256": "\x1b[33m",
+	"sha512": "\x1b[33m",
+}
+
+// ANSIMarker colors matches by kind for review in a terminal.
+func ANSIMarker() Marker {
+	return Marker{Wrap: func(m Match, text string) string {
+		color, ok := ansiColors[m.Type]
+		if !ok {
+			color = "\x1b[32m"
+		}
+		return color + text + "\x1b[0m"
+	}}
+}
+
+// HTMLMarker escapes the text and wraps matches in
+// <mark class="ioc ioc-KIND" data-type="KIND"> elements, for styling in
+// rendered reports.
+func HTMLMarker() Marker {
+	return Marker{
+		Wrap: func(m Match, text string) string {
+			typ := html.EscapeString(m.Type)
+			return `<mark class="ioc ioc-` + typ + `" data-type="` + typ + `">` + text + "</mark>"
+		},
+		Escape: html.EscapeString,
+	}
+}
+
+// DelimiterMarker puts open and close around every match, e.g. "**" and
+// "**" for Markdown bold.
+func DelimiterMarker(open, close string) Marker {
+	return Marker{Wrap: func(_ Match, text string) string {
+		return open + text + close
+	}}
+}
+
+// Annotate returns text with every match highlighted by c.Marker, or by
+// ANSIMarker when it is not set. Overlapping matches are resolved as in
+// Redact, and with Preprocessors set the annotated text is their output.
+func (c *Contextualizer) Annotate(text string) string {
+	marker := c.Marker
+	if marker.Wrap == nil {
+		marker = ANSIMarker()
+	}
+	escape := marker.Escape
+	if escape == nil {
+		escape = func(s string) string { return s }
+	}
+
+	base := c.preprocess(text)
+	var b strings.Builder
+	last := 0
+	for _, m := range c.spans(text, nil) {
+		b.WriteString(escape(base[last:m.Start]))
+		b.WriteString(marker.Wrap(m, escape(base[m.Start:m.End])))
+		last = m.End
+	}
+	b.WriteString(escape(base[last:]))
+	return b.String()
+}
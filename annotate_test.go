@@ -0,0 +1,23 @@
+package parser
+
+import "testing"
+
+func TestContextualizer_Annotate(t *testing.T) {
+	text := "C2 <evil.com> at 198.51.100.4"
+
+	tests := []struct {
+		marker Marker
+		want   string
+	}{
+		{DelimiterMarker("**", "**"), "C2 <**evil.com**> at **198.51.100.4**"},
+		{HTMLMarker(), `C2 &lt;<mark class="ioc ioc-domain" data-type="domain">evil.com</mark>&gt; at ` +
+			`<mark class="ioc ioc-ipv4" data-type="ipv4">198.51.100.4</mark>`},
+		{Marker{}, "C2 <\x1b[31mevil.com\x1b[0m> at \x1b[35m198.51.100.4\x1b[0m"},
+	}
+	for _, tt := range tests {
+		c := NewContextualizer(WithMarker(tt.marker))
+		if got := c.Annotate(text); got != tt.want {
+			t.Errorf("Annotate() = %q, want %q", got, tt.want)
+		}
+	}
+}
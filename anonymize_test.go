@@ -0,0 +1,37 @@
+package parser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAnonymizer(t *testing.T) {
+	c := NewContextualizer()
+	text := "bob@corp.example reached 198.51.100.4 and BOB@corp.example reached 198.51.100.4 again"
+
+	a := NewAnonymizer(c, []byte("secret"))
+	out := a.Anonymize(text)
+	if strings.Contains(out, "bob@") || strings.Contains(out, "198.51.100.4") {
+		t.Fatalf("Anonymize() leaked values: %q", out)
+	}
+	email, ip := a.Token("email", "bob@corp.example"), a.Token("ipv4", "198.51.100.4")
+	if want := email + " reached " + ip + " and " + email + " reached " + ip + " again"; out != want {
+		t.Errorf("Anonymize() = %q, want %q", out, want)
+	}
+	if other := NewAnonymizer(c, []byte("other")).Token("ipv4", "198.51.100.4"); other == ip {
+		t.Error("tokens do not depend on the key")
+	}
+
+	var stored bytes.Buffer
+	if err := a.SaveTokens(&stored); err != nil {
+		t.Fatal(err)
+	}
+	restorer := NewAnonymizer(c, nil)
+	if err := restorer.LoadTokens(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if got := restorer.Restore(out + " [IPV4_0000000000000000]"); got != strings.Replace(text, "BOB", "bob", 1)+" [IPV4_0000000000000000]" {
+		t.Errorf("Restore() = %q", got)
+	}
+}
@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"cmp"
+	"encoding/base64"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Base64Config configures the base64 pre-decoding pass, which decodes
+// large base64 blobs and extracts indicators from their content. Zero
+// fields fall back to the defaults noted below.
+type Base64Config struct {
+	// MinLength is the shortest blob decoded, not counting line breaks.
+	// Defaults to 40.
+	MinLength int `json:"min_length,omitempty"`
+	// MaxDepth bounds how many times base64 nested in decoded content is
+	// decoded again. Defaults to 2.
+	MaxDepth int `json:"max_depth,omitempty"`
+}
+
+// base64Blob matches runs of the standard and URL-safe alphabets, allowing
+// the line breaks of MIME bodies.
+var base64Blob = regexp.MustCompile(`[A-Za-z0-9+/_-]{16,}(?:\r?\n[A-Za-z0-9+/_-]{4,})*={0,2}`)
+
+func (b *Base64Config) limits() (minLength, maxDepth int) {
+	return cmp.Or(b.MinLength, 40), cmp.Or(b.MaxDepth, 2)
+}
+
+// scanBase64 runs the base64 pass over src. seen holds the keys of the
+// matches already reported, see encodedKey, so a value found in the text
+// or in an earlier blob is reported again only with NoDedup.
+func (c *Contextualizer) scanBase64(src source, exprs map[string]*regexp.Regexp, seen map[string]bool, yield func(Match) bool) bool {
+	minLength, maxDepth := c.Base64.limits()
+	return c.scanEncoded(src, exprs, minLength, maxDepth, func(m Match) bool {
+		if !src.noDedup {
+			key := c.encodedKey(m)
+			if seen[key] {
+				return true
+			}
+			seen[key] = true
+		}
+		return yield(m)
+	})
+}
+
+// encodedKey identifies m across the text and the blobs decoded from it.
+// The parent is part of it, so the children of a repeated match are
+// dropped with it.
+func (c *Contextualizer) encodedKey(m Match) string {
+	return m.Type + "\x00" + m.Parent + "\x00" + c.dedupKey(m.Type, m.Value, strings.ToLower(m.Value))
+}
+
+// scanEncoded decodes the base64 blobs of src and runs exprs over their
+// content, down to depth levels of nesting. The matches found are tagged
+// with the "encoded" provenance and span the blob they were decoded from.
+// It reports whether yield wants more.
+func (c *Contextualizer) scanEncoded(src source, exprs map[string]*regexp.Regexp, minLength, depth int, yield func(Match) bool) bool {
+	if depth <= 0 {
+		return true
+	}
+	for _, idx := range base64Blob.FindAllStringIndex(src.text, -1) {
+		decoded, ok := decodeBase64(src.text[idx[0]:idx[1]], minLength)
+		if !ok {
+			continue
+		}
+		start, end := src.offset(idx[0]), src.offset(idx[1])
+		line, column := src.position(start)
+		tag := func(m Match) bool {
+			m.Start, m.End = start, end
+			m.Line, m.Column = line, column
+			m.Raw = ""
+			m.Provenance = "encoded"
+			return yield(m)
+		}
+
+		sub := source{text: decoded, orig: decoded, checks: src.checks, noDedup: src.noDedup, suppressed: src.suppressed}
+		if c.MatchDefanged {
+			sub.text, sub.pos = refang(decoded)
+		}
+		if !c.scanExprs(sub, exprs, c.newResolver(), nil, tag) {
+			return false
+		}
+		if !c.scanEncoded(sub, exprs, minLength, depth-1, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeBase64 decodes blob when it is at least minLength characters long
+// and decodes to text rather than binary data.
+func decodeBase64(blob string, minLength int) (string, bool) {
+	blob = strings.NewReplacer("\r", "", "\n", "").Replace(blob)
+	if len(blob) < minLength {
+		return "", false
+	}
+	encoding := base64.StdEncoding
+	if strings.ContainsAny(blob, "-_") {
+		encoding = base64.URLEncoding
+	}
+	if !strings.HasSuffix(blob, "=") {
+		encoding = encoding.WithPadding(base64.NoPadding)
+	}
+	data, err := encoding.DecodeString(blob)
+	if err != nil || !isText(data) {
+		return "", false
+	}
+	return string(data), true
+}
+
+// isText reports whether data is valid UTF-8 made almost entirely of
+// printable characters and whitespace.
+func isText(data []byte) bool {
+	if !utf8.Valid(data) {
+		return false
+	}
+	var runes, printable int
+	for _, r := range string(data) {
+		runes++
+		if unicode.IsPrint(r) || unicode.IsSpace(r) {
+			printable++
+		}
+	}
+	return runes > 0 && printable*10 >= runes*9
+}
@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestContextualizer_Base64(t *testing.T) {
+	inner := base64.StdEncoding.EncodeToString([]byte("second stage at http://stage2.example.net/p.bin"))
+	payload := base64.StdEncoding.EncodeToString([]byte("click https://phish.example.com/login now, or run " + inner))
+	// Wrap like a MIME body.
+	var wrapped strings.Builder
+	for i := 0; i < len(payload); i += 76 {
+		wrapped.WriteString(payload[i:min(i+76, len(payload))])
+		wrapped.WriteString("\r\n")
+	}
+	text := "Content-Transfer-Encoding: base64\r\n\r\n" + wrapped.String()
+
+	c := NewContextualizer(WithBase64Decoding(Base64Config{}))
+	urls := c.ExtractAll(text)["url"]
+	if len(urls) != 2 {
+		t.Fatalf("url = %+v", urls)
+	}
+	for _, m := range urls {
+		if m.Provenance != "encoded" || !strings.HasPrefix(text[m.Start:m.End], payload[:16]) {
+			t.Errorf("%s: Provenance = %q, span %q", m.Value, m.Provenance, text[m.Start:m.End])
+		}
+	}
+	if urls[0].Value != "https://phish.example.com/login" || urls[1].Value != "http://stage2.example.net/p.bin" {
+		t.Errorf("url = %+v", urls)
+	}
+
+	c = NewContextualizer(WithBase64Decoding(Base64Config{MaxDepth: 1}))
+	if urls := c.ExtractAll(text)["url"]; len(urls) != 1 {
+		t.Errorf("MaxDepth 1: url = %+v", urls)
+	}
+	if urls := NewContextualizer().ExtractAll(text)["url"]; urls != nil {
+		t.Errorf("without Base64: url = %+v", urls)
+	}
+}
+
+func TestContextualizer_Base64Dedup(t *testing.T) {
+	blob := base64.StdEncoding.EncodeToString([]byte("beacon to https://c2.example.net/gate every hour"))
+	text := "seen https://c2.example.net/gate in the logs, then " + blob + " and " + blob
+
+	for _, workers := range []int{0, 4} {
+		c := NewContextualizer(WithBase64Decoding(Base64Config{}), WithWorkers(workers))
+		if urls := c.ExtractAll(text)["url"]; len(urls) != 1 || urls[0].Provenance != "" {
+			t.Errorf("workers=%d: url = %+v, want the plain-text one", workers, urls)
+		}
+		if urls := c.ExtractAllFlags(text, NoDedup)["url"]; len(urls) != 3 {
+			t.Errorf("workers=%d: NoDedup url = %+v, want 3", workers, urls)
+		}
+	}
+
+	c := NewContextualizer(WithBase64Decoding(Base64Config{}))
+	if urls := c.ExtractAll(blob + " and " + blob)["url"]; len(urls) != 1 || urls[0].Provenance != "encoded" {
+		t.Errorf("repeated blob: url = %+v", urls)
+	}
+}
+
+func TestDecodeBase64(t *testing.T) {
+	if _, ok := decodeBase64(base64.StdEncoding.EncodeToString([]byte{0xff, 0x00, 0x13, 0x37, 0x80, 0x81, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89, 0x8a, 0x8b, 0x8c, 0x8d, 0x8e, 0x8f, 0x90, 0x91, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98, 0x99}), 40); ok {
+		t.Error("binary data decoded")
+	}
+	if got, ok := decodeBase64(base64.RawURLEncoding.EncodeToString([]byte("see https://example.org/?q=>>>")), 40); !ok || got != "see https://example.org/?q=>>>" {
+		t.Errorf("decodeBase64() = %q, %v", got, ok)
+	}
+}
@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// Document is a text to extract from in a batch.
+type Document struct {
+	// ID keys the document's BatchResult and becomes the Source of its
+	// Result.
+	ID   string
+	Text string
+	// TLP, when set, marks the document's matches (see Result.TLP).
+	TLP TLP
+}
+
+// BatchResult is the outcome of one document of a batch.
+type BatchResult struct {
+	// Result holds the matches found, possibly partial when Err is set.
+	// It is nil when the document was never scanned.
+	Result *Result
+	// Err is ctx's error when the batch was cancelled before the document
+	// was done, or ErrTruncated when c.Limits cut its scan short.
+	Err error
+}
+
+// ExtractBatch extracts the indicators of docs on up to BatchWorkers
+// goroutines and returns their results keyed by document ID. A failing
+// document doesn't affect the others; the error is only non-nil when two
+// documents share an ID.
+func (c *Contextualizer) ExtractBatch(ctx context.Context, docs []Document) (map[string]BatchResult, error) {
+	out := make(map[string]BatchResult, len(docs))
+	for _, d := range docs {
+		if _, dup := out[d.ID]; dup {
+			return nil, fmt.Errorf("duplicate document ID %q", d.ID)
+		}
+		out[d.ID] = BatchResult{}
+	}
+
+	workers := c.BatchWorkers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	results := make([]BatchResult, len(docs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, len(docs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = c.extractDocument(ctx, docs[i])
+			}
+		}()
+	}
+	for i := range docs {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, d := range docs {
+		out[d.ID] = results[i]
+	}
+	return out, nil
+}
+
+func (c *Contextualizer) extractDocument(ctx context.Context, d Document) BatchResult {
+	if err := ctx.Err(); err != nil {
+		return BatchResult{Err: err}
+	}
+	r := NewResult()
+	r.Source, r.TLP = d.ID, d.TLP
+	c.extractInto(ctx, d.Text, r, 0)
+	if err := ctx.Err(); err != nil {
+		return BatchResult{Result: r, Err: err}
+	}
+	if r.Truncated {
+		return BatchResult{Result: r, Err: ErrTruncated}
+	}
+	return BatchResult{Result: r}
+}
@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestContextualizer_ExtractBatch(t *testing.T) {
+	c := NewContextualizer(WithBatchWorkers(2), WithLimits(Limits{MaxMatches: 2}), WithSourceTags("mail-2", "inbound"))
+	var docs []Document
+	for i := range 5 {
+		docs = append(docs, Document{ID: fmt.Sprintf("mail-%d", i), Text: fmt.Sprintf("host%d.example", i)})
+	}
+	docs = append(docs, Document{ID: "big", Text: "a.example b.example c.example", TLP: TLPAmber})
+
+	got, err := c.ExtractBatch(context.Background(), docs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(docs) {
+		t.Fatalf("ExtractBatch() returned %d results, want %d", len(got), len(docs))
+	}
+	for i := range 5 {
+		id := fmt.Sprintf("mail-%d", i)
+		br := got[id]
+		if br.Err != nil || br.Result.Source != id {
+			t.Errorf("%s = %+v", id, br)
+			continue
+		}
+		if d := br.Result.Matches["domain"]; len(d) != 1 || d[0].Value != fmt.Sprintf("host%d.example", i) {
+			t.Errorf("%s domain = %+v", id, d)
+		}
+	}
+	if tags := got["mail-2"].Result.Matches["domain"][0].Tags; len(tags) != 1 || tags[0] != "inbound" {
+		t.Errorf("mail-2 Tags = %v", tags)
+	}
+	big := got["big"]
+	if !errors.Is(big.Err, ErrTruncated) || big.Result.Len() != 2 || big.Result.Marking() != TLPAmber {
+		t.Errorf("big = %+v, %v", big.Result, big.Err)
+	}
+
+	if _, err := c.ExtractBatch(context.Background(), []Document{{ID: "x"}, {ID: "x"}}); err == nil {
+		t.Error("ExtractBatch accepted duplicate IDs")
+	}
+}
+
+func TestContextualizer_ExtractBatchCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	got, err := NewContextualizer().ExtractBatch(ctx, []Document{{ID: "a", Text: "evil.com"}, {ID: "b", Text: "8.8.8.8"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, br := range got {
+		if !errors.Is(br.Err, context.Canceled) || br.Result != nil {
+			t.Errorf("%s = %+v, want cancelled and unscanned", id, br)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"strings"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// validBitcoinAddress reports whether addr is a mainnet P2PKH/P2SH address
+// with a valid Base58Check checksum or a segwit address with a valid
+// bech32/bech32m checksum.
+func validBitcoinAddress(addr string) bool {
+	if strings.HasPrefix(strings.ToLower(addr), "bc1") {
+		return validBech32Address(addr)
+	}
+	return validBase58Address(addr)
+}
+
+func validBase58Address(addr string) bool {
+	decoded, ok := base58Decode(addr)
+	if !ok || len(decoded) != 25 {
+		return false
+	}
+	if decoded[0] != 0x00 && decoded[0] != 0x05 {
+		return false
+	}
+	first := sha256.Sum256(decoded[:21])
+	second := sha256.Sum256(first[:])
+	return string(second[:4]) == string(decoded[21:])
+}
+
+func base58Decode(s string) ([]byte, bool) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(base58Alphabet, s[i])
+		if digit < 0 {
+			return nil, false
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(digit)))
+	}
+
+	var zeros int
+	for zeros < len(s) && s[zeros] == '1' {
+		zeros++
+	}
+	return append(make([]byte, zeros), n.Bytes()...), true
+}
+
+func validBech32Address(addr string) bool {
+	if addr != strings.ToLower(addr) && addr != strings.ToUpper(addr) {
+		return false
+	}
+	addr = strings.ToLower(addr)
+	sep := strings.LastIndexByte(addr, '1')
+	if sep < 1 || len(addr)-sep < 7 || len(addr) > 90 {
+		return false
+	}
+
+	hrp, data := addr[:sep], addr[sep+1:]
+	values := make([]byte, len(data))
+	for i := 0; i < len(data); i++ {
+		v := strings.IndexByte(bech32Charset, data[i])
+		if v < 0 {
+			return false
+		}
+		values[i] = byte(v)
+	}
+
+	// Witness version 0 uses bech32, later versions use bech32m (BIP-350).
+	want := uint32(1)
+	if values[0] != 0 {
+		want = 0x2bc830a3
+	}
+	return bech32Polymod(append(bech32ExpandHRP(hrp), values...)) == want
+}
+
+func bech32ExpandHRP(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
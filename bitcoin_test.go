@@ -0,0 +1,35 @@
+package parser
+
+import "testing"
+
+func TestValidBitcoinAddress(t *testing.T) {
+	tests := []struct {
+		addr  string
+		valid bool
+	}{
+		{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", true},
+		{"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", true},
+		{"bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", true},
+		{"BC1QAR0SRRR7XFKVY5L643LYDNW9RE59GTZZWF5MDQ", true},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", true},
+		{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb", false},
+		{"bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdr", false},
+		{"Bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", false},
+	}
+
+	for _, tt := range tests {
+		if got := validBitcoinAddress(tt.addr); got != tt.valid {
+			t.Errorf("validBitcoinAddress(%q) = %v, want %v", tt.addr, got, tt.valid)
+		}
+	}
+}
+
+func TestContextualizer_Bitcoin(t *testing.T) {
+	c := NewContextualizer()
+	text := "Pay 1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa, not 1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb"
+
+	got := c.GetMatches(text, "btc", c.Expressions["btc"])
+	if len(got) != 1 || got[0].Value != "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa" {
+		t.Errorf("GetMatches() = %v", got)
+	}
+}
@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltIndicatorStore is a Store persisted in a bbolt database, so
+// collectors keep their sightings across restarts. Each kind has a bucket
+// holding one JSON Sighting per indicator, keyed by its lowercase
+// normalized value, so once the collector has closed the file it can be
+// queried offline, by opening it again or with any bbolt tool. bbolt locks
+// the file, so only one process has it open at a time.
+type BoltIndicatorStore struct {
+	db *bolt.DB
+}
+
+// OpenBoltIndicatorStore opens the store at path, creating the file if
+// needed. It gives up after a second if another process holds the file.
+func OpenBoltIndicatorStore(path string) (*BoltIndicatorStore, error) {
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("opening store %s: %w", path, err)
+	}
+	return &BoltIndicatorStore{db: db}, nil
+}
+
+// Record implements Store. The matches are recorded in a single
+// transaction, so either all of them or none are.
+func (s *BoltIndicatorStore) Record(doc string, at time.Time, matches []Match) error {
+	if len(matches) == 0 {
+		return nil
+	}
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		for _, m := range matches {
+			b, err := tx.CreateBucketIfNotExists([]byte(m.Type))
+			if err != nil {
+				return err
+			}
+			key := []byte(valueKey(m.Type, m.Value))
+			si := newSighting(doc, at, m)
+			if data := b.Get(key); data != nil {
+				var cur Sighting
+				if err := json.Unmarshal(data, &cur); err != nil {
+					return err
+				}
+				cur.merge(si)
+				si = cur
+			}
+			data, err := json.Marshal(si)
+			if err != nil {
+				return err
+			}
+			if err := b.Put(key, data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("writing store: %w", err)
+	}
+	return nil
+}
+
+// Lookup implements Store.
+func (s *BoltIndicatorStore) Lookup(kind, value string) (Sighting, bool, error) {
+	var si Sighting
+	var ok bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(kind))
+		if b == nil {
+			return nil
+		}
+		data := b.Get([]byte(valueKey(kind, value)))
+		if data == nil {
+			return nil
+		}
+		ok = true
+		return json.Unmarshal(data, &si)
+	})
+	if err != nil {
+		return Sighting{}, false, fmt.Errorf("reading store: %w", err)
+	}
+	return si, ok, nil
+}
+
+// Sightings implements Store.
+func (s *BoltIndicatorStore) Sightings(kind string) ([]Sighting, error) {
+	var out []Sighting
+	collect := func(b *bolt.Bucket) error {
+		return b.ForEach(func(_, data []byte) error {
+			var si Sighting
+			if err := json.Unmarshal(data, &si); err != nil {
+				return err
+			}
+			out = append(out, si)
+			return nil
+		})
+	}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if kind != "" {
+			if b := tx.Bucket([]byte(kind)); b != nil {
+				return collect(b)
+			}
+			return nil
+		}
+		return tx.ForEach(func(_ []byte, b *bolt.Bucket) error { return collect(b) })
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading store: %w", err)
+	}
+	sortSightings(out)
+	return out, nil
+}
+
+// Close closes the database. The store can't be used after that.
+func (s *BoltIndicatorStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBoltIndicatorStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sightings.db")
+	c := NewContextualizer()
+	t0 := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	s, err := OpenBoltIndicatorStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Record("msg-1", t0, c.ExtractOrdered("beacon to evil.com from 8.8.8.8")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Record("msg-2", t0.Add(time.Hour), c.ExtractOrdered("EVIL.com again")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Record("msg-3", t0, c.ExtractOrdered("evil.com")); err == nil {
+		t.Error("Record() after Close succeeded")
+	}
+
+	// Reopened, as an offline reader would.
+	s, err = OpenBoltIndicatorStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	got, ok, err := s.Lookup("domain", "Evil.COM")
+	if err != nil || !ok {
+		t.Fatalf("Lookup() = %v, %v", ok, err)
+	}
+	if got.Value != "evil.com" || got.Hits != 2 || !got.FirstSeen.Equal(t0) || !got.LastSeen.Equal(t0.Add(time.Hour)) || len(got.Documents) != 2 {
+		t.Errorf("Lookup() = %+v", got)
+	}
+	if _, ok, err := s.Lookup("md5", "d41d8cd98f00b204e9800998ecf8427e"); ok || err != nil {
+		t.Errorf("Lookup() of an unknown kind = %v, %v", ok, err)
+	}
+	if all, err := s.Sightings(""); err != nil || len(all) != 2 || all[0].Type != "domain" || all[1].Type != "ipv4" {
+		t.Errorf("Sightings() = %+v, %v", all, err)
+	}
+	if ips, err := s.Sightings("ipv4"); err != nil || len(ips) != 1 || ips[0].Value != "8.8.8.8" {
+		t.Errorf("Sightings(ipv4) = %+v, %v", ips, err)
+	}
+	if seen, err := (Query{Document: "msg-2"}).Run(s); err != nil || len(seen) != 1 || seen[0].Value != "evil.com" {
+		t.Errorf("Query.Run() = %+v, %v", seen, err)
+	}
+}
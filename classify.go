@@ -0,0 +1,37 @@
+package parser
+
+import "slices"
+
+// Classification is the severity and tags assigned to a kind, copied into
+// every match of that kind so SIEM routing rules can key off them.
+type Classification struct {
+	// Severity is a label such as "high", "medium" or "info".
+	Severity string `json:"severity,omitempty"`
+	// Tags are free-form labels such as "network" or "pii".
+	Tags []string `json:"tags,omitempty"`
+}
+
+// classify fills the Severity and Tags of m from c.Classifications. The
+// caller holds c.mu for reading.
+func (c *Contextualizer) classify(m Match) Match {
+	cl, ok := c.Classifications[m.Type]
+	if !ok {
+		return m
+	}
+	m.Severity = cl.Severity
+	// Clipped so that appending to one match's tags never writes into
+	// another's.
+	m.Tags = slices.Clip(cl.Tags)
+	return m
+}
+
+func cloneClassifications(cls map[string]Classification) map[string]Classification {
+	if cls == nil {
+		return nil
+	}
+	clone := make(map[string]Classification, len(cls))
+	for kind, cl := range cls {
+		clone[kind] = Classification{Severity: cl.Severity, Tags: slices.Clone(cl.Tags)}
+	}
+	return clone
+}
@@ -0,0 +1,26 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContextualizer_Classifications(t *testing.T) {
+	c := NewContextualizer(
+		WithClassification("sha256", Classification{Severity: "high", Tags: []string{"hash", "malware"}}),
+		WithClassification("base_domain", Classification{Severity: "medium"}),
+		WithFilters(func(m Match) (Match, bool) { return m, m.Severity != "" }),
+	)
+	got := c.ExtractAll("e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855 on a.evil.com at /tmp/x/y")
+
+	if s := got["sha256"]; len(s) != 1 || s[0].Severity != "high" || !reflect.DeepEqual(s[0].Tags, []string{"hash", "malware"}) {
+		t.Errorf("sha256 = %+v", s)
+	}
+	if b := got["base_domain"]; len(b) != 1 || b[0].Severity != "medium" || b[0].Tags != nil {
+		t.Errorf("base_domain = %+v", b)
+	}
+	// Filters see the classification, so unclassified kinds are dropped.
+	if got["domain"] != nil || got["filepath"] != nil {
+		t.Errorf("unclassified kinds kept: %v", got)
+	}
+}
@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"maps"
+	"slices"
+)
+
+// Clone returns a deep copy of c. Expression tables, ignore sets, national
+// ID validators and configuration structs are copied, so a base
+// configuration can be forked per tenant or per request and changed
+// without affecting the original. Compiled expressions are shared, which
+// is safe since they are immutable.
+func (c *Contextualizer) Clone() *Contextualizer {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	clone := &Contextualizer{
+		ID:                  c.ID,
+		Expressions:         maps.Clone(c.Expressions),
+		disabled:            maps.Clone(c.disabled),
+		nationalIDs:         maps.Clone(c.nationalIDs),
+		extractors:          maps.Clone(c.extractors),
+		validators:          cloneValidators(c.validators),
+		enrichers:           slices.Clone(c.enrichers),
+		EnrichWorkers:       c.EnrichWorkers,
+		BatchWorkers:        c.BatchWorkers,
+		ChunkSize:           c.ChunkSize,
+		ChunkOverlap:        c.ChunkOverlap,
+		prefilters:          maps.Clone(c.prefilters),
+		MatchDefanged:       c.MatchDefanged,
+		DecodeEscapes:       c.DecodeEscapes,
+		StripHTML:           c.StripHTML,
+		Preprocessors:       slices.Clone(c.Preprocessors),
+		Filters:             slices.Clone(c.Filters),
+		DefangOutput:        c.DefangOutput,
+		VerifyEIP55:         c.VerifyEIP55,
+		RequireKnownTLD:     c.RequireKnownTLD,
+		DetectGitCommits:    c.DetectGitCommits,
+		DecodeJWT:           c.DecodeJWT,
+		DecomposeURLs:       c.DecomposeURLs,
+		RedactPEM:           c.RedactPEM,
+		ContextWindow:       c.ContextWindow,
+		SentenceContext:     c.SentenceContext,
+		LineNumbers:         c.LineNumbers,
+		ScoreMatches:        c.ScoreMatches,
+		HashHeuristics:      c.HashHeuristics,
+		KindPriority:        slices.Clone(c.KindPriority),
+		FileExtensions:      slices.Clone(c.FileExtensions),
+		PreserveCase:        c.PreserveCase,
+		Normalize:           c.Normalize,
+		Normalizers:         maps.Clone(c.Normalizers),
+		KeywordHints:        cloneKeywordHints(c.KeywordHints),
+		Marker:              c.Marker,
+		Classifications:     cloneClassifications(c.Classifications),
+		TLP:                 c.TLP,
+		Taggers:             slices.Clone(c.Taggers),
+		SourceTags:          cloneSourceTags(c.SourceTags),
+		StripPlusAddressing: c.StripPlusAddressing,
+		GmailDots:           c.GmailDots,
+		CanonicalURLs:       c.CanonicalURLs,
+		EquivalentURLs:      c.EquivalentURLs,
+		Workers:             c.Workers,
+		DisablePrefilter:    c.DisablePrefilter,
+		configPath:          c.configPath,
+		configOpts:          c.configOpts,
+		configInfo:          c.configInfo,
+	}
+	if c.Checks != nil {
+		clone.Checks = c.Checks.clone()
+	}
+	if c.added != nil {
+		clone.added = c.added.clone()
+	}
+	clone.exprChanges = maps.Clone(c.exprChanges)
+	clone.kindChanges = maps.Clone(c.kindChanges)
+	if c.Entropy != nil {
+		entropy := *c.Entropy
+		clone.Entropy = &entropy
+	}
+	if c.Base64 != nil {
+		b64 := *c.Base64
+		clone.Base64 = &b64
+	}
+	if c.Limits != nil {
+		limits := *c.Limits
+		limits.PerKind = maps.Clone(c.Limits.PerKind)
+		clone.Limits = &limits
+	}
+	return clone
+}
+
+func cloneKeywordHints(hints map[string][]KeywordHint) map[string][]KeywordHint {
+	if hints == nil {
+		return nil
+	}
+	clone := make(map[string][]KeywordHint, len(hints))
+	for kind, h := range hints {
+		clone[kind] = slices.Clone(h)
+	}
+	return clone
+}
+
+func cloneValidators(validators map[string][]Validator) map[string][]Validator {
+	if validators == nil {
+		return nil
+	}
+	clone := make(map[string][]Validator, len(validators))
+	for kind, v := range validators {
+		clone[kind] = slices.Clone(v)
+	}
+	return clone
+}
@@ -0,0 +1,41 @@
+package parser
+
+import "testing"
+
+func TestContextualizer_Clone(t *testing.T) {
+	base := NewContextualizer(
+		WithIgnoredDomains("corp.example"),
+		WithLimits(Limits{PerKind: map[string]int{"ipv4": 5}}),
+	)
+	tenant := base.Clone()
+
+	tenant.Checks.IgnoredDomains["tenant.example"] = struct{}{}
+	tenant.Limits.PerKind["ipv4"] = 1
+	if err := tenant.AddExpression("ticket", `\b(INC\d{6})\b`); err != nil {
+		t.Fatal(err)
+	}
+	tenant.RemoveExpression("md5")
+
+	if _, ok := base.Checks.IgnoredDomains["tenant.example"]; ok {
+		t.Error("ignore set is shared with the clone")
+	}
+	if base.Limits.PerKind["ipv4"] != 5 {
+		t.Error("limits are shared with the clone")
+	}
+	if _, ok := base.Expressions["ticket"]; ok {
+		t.Error("expression added to the clone leaked into the original")
+	}
+	if _, ok := base.Expressions["md5"]; !ok {
+		t.Error("expression removed from the clone was removed from the original")
+	}
+
+	if got := tenant.ExtractAll("see INC123456 on corp.example")["ticket"]; len(got) != 1 {
+		t.Errorf("clone ticket matches = %v", got)
+	}
+	if got := tenant.ExtractAll("d41d8cd98f00b204e9800998ecf8427e")["md5"]; got != nil {
+		t.Errorf("clone md5 matches = %v", got)
+	}
+	if got := base.ExtractAll("d41d8cd98f00b204e9800998ecf8427e")["md5"]; len(got) != 1 {
+		t.Errorf("base md5 matches = %v", got)
+	}
+}
@@ -0,0 +1,476 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ConfigVersion is the version of the Config schema written by
+// MarshalConfig.
+const ConfigVersion = 1
+
+// Config is the serializable form of a Contextualizer: its full pattern
+// set, PrivateChecks and options. National ID validators and a
+// HashAllowlist are code and are not part of it; the expressions of
+// national IDs are, so a restored Contextualizer matches them but skips the
+// checksum check.
+type Config struct {
+	Version             int                       `json:"version"`
+	ID                  string                    `json:"id,omitempty"`
+	Expressions         map[string]string         `json:"expressions,omitempty"`
+	DisabledKinds       []string                  `json:"disabled_kinds,omitempty"`
+	IgnorePrivateIPs    bool                      `json:"ignore_private_ips,omitempty"`
+	IgnoreBogons        bool                      `json:"ignore_bogons,omitempty"`
+	IgnoredDomains      []string                  `json:"ignored_domains,omitempty"`
+	IgnoredEmails       []string                  `json:"ignored_emails,omitempty"`
+	IgnoredIPs          []string                  `json:"ignored_ips,omitempty"`
+	IgnoredCIDRs        []string                  `json:"ignored_cidrs,omitempty"`
+	IgnorePatterns      map[string][]string       `json:"ignore_patterns,omitempty"`
+	IgnoredHashes       []string                  `json:"ignored_hashes,omitempty"`
+	TopDomains          []string                  `json:"top_domains,omitempty"`
+	IgnoreLocalMACs     bool                      `json:"ignore_local_macs,omitempty"`
+	MatchDefanged       bool                      `json:"match_defanged,omitempty"`
+	DecodeEscapes       bool                      `json:"decode_escapes,omitempty"`
+	StripHTML           bool                      `json:"strip_html,omitempty"`
+	DefangOutput        bool                      `json:"defang_output,omitempty"`
+	VerifyEIP55         bool                      `json:"verify_eip55,omitempty"`
+	RequireKnownTLD     bool                      `json:"require_known_tld,omitempty"`
+	DetectGitCommits    bool                      `json:"detect_git_commits,omitempty"`
+	DecodeJWT           bool                      `json:"decode_jwt,omitempty"`
+	DecomposeURLs       bool                      `json:"decompose_urls,omitempty"`
+	Entropy             *EntropyConfig            `json:"entropy,omitempty"`
+	Base64              *Base64Config             `json:"base64,omitempty"`
+	RedactPEM           bool                      `json:"redact_pem,omitempty"`
+	ContextWindow       int                       `json:"context_window,omitempty"`
+	SentenceContext     bool                      `json:"sentence_context,omitempty"`
+	LineNumbers         bool                      `json:"line_numbers,omitempty"`
+	ScoreMatches        bool                      `json:"score_matches,omitempty"`
+	HashHeuristics      bool                      `json:"hash_heuristics,omitempty"`
+	KeywordHints        map[string][]KeywordHint  `json:"keyword_hints,omitempty"`
+	Classifications     map[string]Classification `json:"classifications,omitempty"`
+	SourceTags          map[string][]string       `json:"source_tags,omitempty"`
+	TLP                 TLP                       `json:"tlp,omitempty"`
+	KindPriority        []string                  `json:"kind_priority,omitempty"`
+	FileExtensions      []string                  `json:"file_extensions,omitempty"`
+	PreserveCase        bool                      `json:"preserve_case,omitempty"`
+	Normalize           bool                      `json:"normalize,omitempty"`
+	StripPlusAddressing bool                      `json:"strip_plus_addressing,omitempty"`
+	GmailDots           bool                      `json:"gmail_dots,omitempty"`
+	CanonicalURLs       bool                      `json:"canonical_urls,omitempty"`
+	EquivalentURLs      bool                      `json:"equivalent_urls,omitempty"`
+	Workers             int                       `json:"workers,omitempty"`
+	BatchWorkers        int                       `json:"batch_workers,omitempty"`
+	ChunkSize           int                       `json:"chunk_size,omitempty"`
+	ChunkOverlap        int                       `json:"chunk_overlap,omitempty"`
+	DisablePrefilter    bool                      `json:"disable_prefilter,omitempty"`
+	Limits              *Limits                   `json:"limits,omitempty"`
+}
+
+// Config returns the current configuration of c.
+func (c *Contextualizer) Config() Config {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	cfg := Config{
+		Version:             ConfigVersion,
+		ID:                  c.ID,
+		Expressions:         make(map[string]string),
+		MatchDefanged:       c.MatchDefanged,
+		DecodeEscapes:       c.DecodeEscapes,
+		StripHTML:           c.StripHTML,
+		DefangOutput:        c.DefangOutput,
+		VerifyEIP55:         c.VerifyEIP55,
+		RequireKnownTLD:     c.RequireKnownTLD,
+		DetectGitCommits:    c.DetectGitCommits,
+		DecodeJWT:           c.DecodeJWT,
+		DecomposeURLs:       c.DecomposeURLs,
+		RedactPEM:           c.RedactPEM,
+		ContextWindow:       c.ContextWindow,
+		SentenceContext:     c.SentenceContext,
+		LineNumbers:         c.LineNumbers,
+		ScoreMatches:        c.ScoreMatches,
+		HashHeuristics:      c.HashHeuristics,
+		KeywordHints:        cloneKeywordHints(c.KeywordHints),
+		Classifications:     cloneClassifications(c.Classifications),
+		SourceTags:          cloneSourceTags(c.SourceTags),
+		TLP:                 c.TLP,
+		KindPriority:        slices.Clone(c.KindPriority),
+		FileExtensions:      slices.Clone(c.FileExtensions),
+		PreserveCase:        c.PreserveCase,
+		Normalize:           c.Normalize,
+		StripPlusAddressing: c.StripPlusAddressing,
+		GmailDots:           c.GmailDots,
+		CanonicalURLs:       c.CanonicalURLs,
+		EquivalentURLs:      c.EquivalentURLs,
+		Workers:             c.Workers,
+		BatchWorkers:        c.BatchWorkers,
+		ChunkSize:           c.ChunkSize,
+		ChunkOverlap:        c.ChunkOverlap,
+		DisablePrefilter:    c.DisablePrefilter,
+	}
+	if c.Entropy != nil {
+		entropy := *c.Entropy
+		cfg.Entropy = &entropy
+	}
+	if c.Base64 != nil {
+		b64 := *c.Base64
+		cfg.Base64 = &b64
+	}
+	if c.Limits != nil {
+		limits := *c.Limits
+		limits.PerKind = maps.Clone(c.Limits.PerKind)
+		cfg.Limits = &limits
+	}
+	for kind, regex := range c.Expressions {
+		cfg.Expressions[kind] = regex.String()
+	}
+	cfg.DisabledKinds = slices.Sorted(maps.Keys(c.disabled))
+	if c.Checks != nil {
+		cfg.IgnorePrivateIPs = c.Checks.IgnorePrivateIPs
+		cfg.IgnoreBogons = c.Checks.IgnoreBogons
+		cfg.IgnoredDomains = slices.Sorted(maps.Keys(c.Checks.IgnoredDomains))
+		cfg.IgnoredEmails = slices.Sorted(maps.Keys(c.Checks.IgnoredEmails))
+		for _, addr := range slices.SortedFunc(maps.Keys(c.Checks.IgnoredIPs), netip.Addr.Compare) {
+			cfg.IgnoredIPs = append(cfg.IgnoredIPs, addr.String())
+		}
+		for _, prefix := range c.Checks.IgnoredCIDRs {
+			cfg.IgnoredCIDRs = append(cfg.IgnoredCIDRs, prefix.String())
+		}
+		cfg.IgnoredHashes = slices.Sorted(maps.Keys(c.Checks.IgnoredHashes))
+		cfg.TopDomains = slices.Sorted(maps.Keys(c.Checks.TopDomains))
+		for kind, regexes := range c.Checks.IgnoredPatterns {
+			if cfg.IgnorePatterns == nil {
+				cfg.IgnorePatterns = make(map[string][]string)
+			}
+			for _, regex := range regexes {
+				cfg.IgnorePatterns[kind] = append(cfg.IgnorePatterns[kind], regex.String())
+			}
+		}
+		cfg.IgnoreLocalMACs = c.Checks.IgnoreLocalMACs
+	}
+	return cfg
+}
+
+// ApplyConfig replaces the pattern set, PrivateChecks and options of c with
+// those in cfg. Every expression is compiled before anything changes, so a
+// bad pattern leaves c untouched. Patterns identical to the ones already
+// registered keep their compiled expression and prefilter. Entries added
+// by the Load* methods, and the expression changes Reload would apply
+// again, are dropped unless cfg lists them, as it does when it came from
+// Config.
+//
+// Everything is swapped under c.mu, but extractions read most options
+// without it, so apply a config before c is shared, or to a Clone that
+// then replaces c. Reload only swaps what extractions read under c.mu.
+func (c *Contextualizer) ApplyConfig(cfg Config) error {
+	if cfg.Version > ConfigVersion {
+		return fmt.Errorf("unsupported config version %d", cfg.Version)
+	}
+	tlp, err := ParseTLP(string(cfg.TLP))
+	if err != nil {
+		return err
+	}
+	c.mu.RLock()
+	existing := maps.Clone(c.Expressions)
+	c.mu.RUnlock()
+	exprs, err := compileExpressions(cfg.Expressions, existing)
+	if err != nil {
+		return err
+	}
+	if err := validIPs(cfg.IgnoredIPs); err != nil {
+		return err
+	}
+	cidrs, err := parseCIDRs(cfg.IgnoredCIDRs)
+	if err != nil {
+		return err
+	}
+	patterns, err := compileIgnorePatterns(cfg.IgnorePatterns)
+	if err != nil {
+		return err
+	}
+
+	checks := &PrivateChecks{
+		IgnorePrivateIPs: cfg.IgnorePrivateIPs,
+		IgnoreBogons:     cfg.IgnoreBogons,
+		IgnoredDomains:   make(map[string]struct{}, len(cfg.IgnoredDomains)),
+		IgnoredEmails:    make(map[string]struct{}, len(cfg.IgnoredEmails)),
+		IgnoreLocalMACs:  cfg.IgnoreLocalMACs,
+	}
+	checks.ignoreDomains(cfg.IgnoredDomains...)
+	checks.ignoreEmails(cfg.IgnoredEmails...)
+	checks.ignoreIPs(cfg.IgnoredIPs...)
+	checks.ignoreCIDRs(cidrs...)
+	checks.ignoreHashes(cfg.IgnoredHashes...)
+	for _, d := range cfg.TopDomains {
+		if checks.TopDomains == nil {
+			checks.TopDomains = make(map[string]struct{}, len(cfg.TopDomains))
+		}
+		checks.TopDomains[strings.ToLower(d)] = struct{}{}
+	}
+	checks.HashAllowlist = c.checks().HashAllowlist
+	for kind, regexes := range patterns {
+		checks.ignorePatterns(kind, regexes...)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	prefilters := make(map[*regexp.Regexp]prefilter)
+	for _, regex := range exprs {
+		if pf, ok := c.prefilters[regex]; ok {
+			prefilters[regex] = pf
+		}
+	}
+	c.Expressions = exprs
+	c.prefilters = prefilters
+	c.disabled = nil
+	for _, kind := range cfg.DisabledKinds {
+		if c.disabled == nil {
+			c.disabled = make(map[string]struct{}, len(cfg.DisabledKinds))
+		}
+		c.disabled[kind] = struct{}{}
+	}
+	c.Checks = checks
+	c.added, c.exprChanges, c.kindChanges = nil, nil, nil
+	if cfg.ID != "" {
+		c.ID = cfg.ID
+	}
+	c.MatchDefanged = cfg.MatchDefanged
+	c.DecodeEscapes = cfg.DecodeEscapes
+	c.StripHTML = cfg.StripHTML
+	c.DefangOutput = cfg.DefangOutput
+	c.VerifyEIP55 = cfg.VerifyEIP55
+	c.RequireKnownTLD = cfg.RequireKnownTLD
+	c.DetectGitCommits = cfg.DetectGitCommits
+	c.DecodeJWT = cfg.DecodeJWT
+	c.DecomposeURLs = cfg.DecomposeURLs
+	c.Entropy = nil
+	if cfg.Entropy != nil {
+		entropy := *cfg.Entropy
+		c.Entropy = &entropy
+	}
+	c.Base64 = nil
+	if cfg.Base64 != nil {
+		b64 := *cfg.Base64
+		c.Base64 = &b64
+	}
+	c.RedactPEM = cfg.RedactPEM
+	c.ContextWindow = cfg.ContextWindow
+	c.SentenceContext = cfg.SentenceContext
+	c.LineNumbers = cfg.LineNumbers
+	c.ScoreMatches = cfg.ScoreMatches
+	c.HashHeuristics = cfg.HashHeuristics
+	c.KeywordHints = cloneKeywordHints(cfg.KeywordHints)
+	c.Classifications = cloneClassifications(cfg.Classifications)
+	c.SourceTags = cloneSourceTags(cfg.SourceTags)
+	c.TLP = tlp
+	c.KindPriority = slices.Clone(cfg.KindPriority)
+	c.FileExtensions = slices.Clone(cfg.FileExtensions)
+	c.PreserveCase = cfg.PreserveCase
+	c.Normalize = cfg.Normalize
+	c.StripPlusAddressing = cfg.StripPlusAddressing
+	c.GmailDots = cfg.GmailDots
+	c.CanonicalURLs = cfg.CanonicalURLs
+	c.EquivalentURLs = cfg.EquivalentURLs
+	c.Workers = cfg.Workers
+	c.BatchWorkers = cfg.BatchWorkers
+	c.ChunkSize = cfg.ChunkSize
+	c.ChunkOverlap = cfg.ChunkOverlap
+	c.DisablePrefilter = cfg.DisablePrefilter
+	c.Limits = nil
+	if cfg.Limits != nil {
+		limits := *cfg.Limits
+		limits.PerKind = maps.Clone(cfg.Limits.PerKind)
+		c.Limits = &limits
+	}
+	return nil
+}
+
+// MarshalConfig encodes the configuration of c as JSON.
+func (c *Contextualizer) MarshalConfig() ([]byte, error) {
+	return json.MarshalIndent(c.Config(), "", "  ")
+}
+
+// UnmarshalConfig decodes a configuration written by MarshalConfig and
+// applies it to c.
+func (c *Contextualizer) UnmarshalConfig(data []byte) error {
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("decoding config: %w", err)
+	}
+	return c.ApplyConfig(cfg)
+}
+
+// compileExpressions compiles patterns, reusing the expression in existing
+// when a kind's pattern is unchanged.
+func compileExpressions(patterns map[string]string, existing map[string]*regexp.Regexp) (map[string]*regexp.Regexp, error) {
+	exprs := make(map[string]*regexp.Regexp, len(patterns))
+	for kind, pattern := range patterns {
+		if regex, ok := existing[kind]; ok && regex.String() == pattern {
+			exprs[kind] = regex
+			continue
+		}
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("compiling expression %q: %w", kind, err)
+		}
+		exprs[kind] = regex
+	}
+	return exprs, nil
+}
+
+// FileConfig is the schema of the files read by LoadConfig. Unlike Config
+// it describes changes to the built-in configuration rather than a full
+// pattern set, so files only need to mention what differs.
+type FileConfig struct {
+	// Expressions adds kinds or overrides the pattern of built-in ones.
+	// The first capture group, if any, delimits the value.
+	Expressions map[string]string `json:"expressions,omitempty"`
+	// Disable turns off kinds, built-in or from Profiles (see DisableKind).
+	Disable []string `json:"disable,omitempty"`
+	// Profiles enables detector profiles such as "secrets" or "pii".
+	Profiles         []string `json:"profiles,omitempty"`
+	IgnorePrivateIPs bool     `json:"ignore_private_ips,omitempty"`
+	IgnoreBogons     bool     `json:"ignore_bogons,omitempty"`
+	IgnoredDomains   []string `json:"ignored_domains,omitempty"`
+	IgnoredEmails    []string `json:"ignored_emails,omitempty"`
+	IgnoredIPs       []string `json:"ignored_ips,omitempty"`
+	IgnoredCIDRs     []string `json:"ignored_cidrs,omitempty"`
+	// IgnorePatterns maps kinds to expressions of values to drop.
+	IgnorePatterns  map[string][]string `json:"ignore_patterns,omitempty"`
+	IgnoredHashes   []string            `json:"ignored_hashes,omitempty"`
+	IgnoreLocalMACs bool                `json:"ignore_local_macs,omitempty"`
+	// Classifications assigns severities and tags to kinds, e.g.
+	// {"sha256": {"severity": "high"}, "filepath": {"severity": "info"}}.
+	Classifications map[string]Classification `json:"classifications,omitempty"`
+	// SourceTags maps Result sources to tags, e.g. {"gateway": ["email"]}.
+	SourceTags map[string][]string `json:"source_tags,omitempty"`
+	// TLP marks every match, e.g. "TLP:AMBER" (see ParseTLP).
+	TLP string `json:"tlp,omitempty"`
+}
+
+// LoadConfig returns a Contextualizer configured by opts and then by the
+// JSON file at path (see FileConfig). Unknown fields are rejected so typos
+// don't go unnoticed.
+func LoadConfig(path string, opts ...Option) (*Contextualizer, error) {
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		return nil, fmt.Errorf("loading config %s: YAML is not supported, use JSON", path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	var fc FileConfig
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&fc); err != nil {
+		return nil, fmt.Errorf("decoding config %s: %w", path, err)
+	}
+
+	c := NewContextualizer(opts...)
+	if err := c.applyFileConfig(fc); err != nil {
+		return nil, fmt.Errorf("loading config %s: %w", path, err)
+	}
+	c.configPath, c.configOpts, c.configInfo = path, opts, info
+	// Only the changes made from here on are applied again by Reload.
+	c.added, c.exprChanges, c.kindChanges = nil, nil, nil
+	return c, nil
+}
+
+func (c *Contextualizer) applyFileConfig(fc FileConfig) error {
+	if err := validIPs(fc.IgnoredIPs); err != nil {
+		return err
+	}
+	cidrs, err := parseCIDRs(fc.IgnoredCIDRs)
+	if err != nil {
+		return err
+	}
+	patterns, err := compileIgnorePatterns(fc.IgnorePatterns)
+	if err != nil {
+		return err
+	}
+	tlp, err := ParseTLP(fc.TLP)
+	if err != nil {
+		return err
+	}
+	for _, name := range fc.Profiles {
+		if err := c.EnableProfile(name); err != nil {
+			return err
+		}
+	}
+	for _, kind := range slices.Sorted(maps.Keys(fc.Expressions)) {
+		if err := c.AddExpression(kind, fc.Expressions[kind]); err != nil {
+			return err
+		}
+	}
+	for _, kind := range fc.Disable {
+		c.DisableKind(kind)
+	}
+	c.Checks.IgnorePrivateIPs = c.Checks.IgnorePrivateIPs || fc.IgnorePrivateIPs
+	c.Checks.IgnoreBogons = c.Checks.IgnoreBogons || fc.IgnoreBogons
+	c.Checks.IgnoreLocalMACs = c.Checks.IgnoreLocalMACs || fc.IgnoreLocalMACs
+	c.Checks.ignoreDomains(fc.IgnoredDomains...)
+	c.Checks.ignoreEmails(fc.IgnoredEmails...)
+	c.Checks.ignoreIPs(fc.IgnoredIPs...)
+	c.Checks.ignoreCIDRs(cidrs...)
+	c.Checks.ignoreHashes(fc.IgnoredHashes...)
+	for kind, regexes := range patterns {
+		c.Checks.ignorePatterns(kind, regexes...)
+	}
+	for kind, cl := range fc.Classifications {
+		WithClassification(kind, cl)(c)
+	}
+	if tlp != "" {
+		c.TLP = tlp
+	}
+	for source, tags := range fc.SourceTags {
+		WithSourceTags(source, tags...)(c)
+	}
+	return nil
+}
+
+func validIPs(ips []string) error {
+	for _, ip := range ips {
+		if _, err := netip.ParseAddr(ip); err != nil {
+			return fmt.Errorf("ignored IP: %w", err)
+		}
+	}
+	return nil
+}
+
+func parseCIDRs(cidrs []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("ignored CIDR: %w", err)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes, nil
+}
+
+func compileIgnorePatterns(patterns map[string][]string) (map[string][]*regexp.Regexp, error) {
+	compiled := make(map[string][]*regexp.Regexp, len(patterns))
+	for kind, list := range patterns {
+		for _, pattern := range list {
+			regex, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("compiling ignore pattern for %q: %w", kind, err)
+			}
+			compiled[kind] = append(compiled[kind], regex)
+		}
+	}
+	return compiled, nil
+}
@@ -0,0 +1,152 @@
+package parser
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestContextualizer_ConfigRoundTrip(t *testing.T) {
+	orig := NewContextualizer(
+		WithIgnorePrivateIPs(true),
+		WithIgnoredDomains("corp.example", "Example.NET"),
+		WithIgnoredEmails("soc@corp.example"),
+		WithDefanged(),
+		WithEntropy(EntropyConfig{MinLength: 24}),
+		WithLimits(Limits{MaxMatches: 100, PerKind: map[string]int{"ipv4": 10}}),
+		WithIgnoredCIDRs(netip.MustParsePrefix("203.0.113.0/24")),
+		WithTopDomainAllowlist(strings.NewReader("1,google.com\n"), 0),
+	)
+	if err := orig.AddIgnorePattern("url", `/healthz`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := orig.LoadIgnoredIPs(strings.NewReader("1.1.1.1\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := orig.AddExpression("ticket", `\b(INC\d{6})\b`); err != nil {
+		t.Fatal(err)
+	}
+	orig.RemoveExpression("filename")
+
+	data, err := orig.MarshalConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := NewContextualizer()
+	if err := restored.UnmarshalConfig(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(restored.Config(), orig.Config()) {
+		t.Errorf("restored config = %+v, want %+v", restored.Config(), orig.Config())
+	}
+	if !reflect.DeepEqual(restored.Kinds(), orig.Kinds()) {
+		t.Errorf("restored kinds = %v, want %v", restored.Kinds(), orig.Kinds())
+	}
+
+	text := "INC123456 from 10.0.0.1 and 8.8.8.8 via hxxp://evil[.]com/x, cc soc@corp.example"
+	if got, want := restored.ExtractAll(text), orig.ExtractAll(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("restored ExtractAll() = %v, want %v", got, want)
+	}
+}
+
+func TestContextualizer_UnmarshalConfigErrors(t *testing.T) {
+	c := NewContextualizer()
+	kinds := c.Kinds()
+
+	for _, data := range []string{
+		`{"version": 1, "expressions": {"bad": "("}}`,
+		`{"version": 99}`,
+		`not json`,
+	} {
+		if err := c.UnmarshalConfig([]byte(data)); err == nil {
+			t.Errorf("UnmarshalConfig(%s) succeeded", data)
+		}
+	}
+	if !reflect.DeepEqual(c.Kinds(), kinds) {
+		t.Errorf("failed UnmarshalConfig changed kinds to %v", c.Kinds())
+	}
+}
+
+func TestContextualizer_MarshalConfigStable(t *testing.T) {
+	c := NewContextualizer(WithIgnoredDomains("b.example", "a.example"))
+	first, _ := c.MarshalConfig()
+	second, _ := c.MarshalConfig()
+	if string(first) != string(second) {
+		t.Error("MarshalConfig output is not stable")
+	}
+	if !strings.Contains(string(first), `"a.example",`) {
+		t.Errorf("ignored domains not sorted: %s", first)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "parser.json")
+	data := `{
+		"expressions": {
+			"ticket": "\\b(INC\\d{6})\\b",
+			"md5": "(?i)\\bmd5:([a-f\\d]{32})\\b"
+		},
+		"disable": ["filename", "filepath"],
+		"profiles": ["secrets"],
+		"ignored_domains": ["corp.example"],
+		"ignore_private_ips": true,
+		"classifications": {"md5": {"severity": "high", "tags": ["hash"]}},
+		"source_tags": {"gateway": ["email"]},
+		"tlp": "TLP:AMBER"
+	}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := LoadConfig(path, WithIgnoredEmails("soc@corp.example"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(c.Kinds(), "aws_access_key") || slices.Contains(c.Kinds(), "filename") {
+		t.Errorf("kinds = %v", c.Kinds())
+	}
+
+	got := c.ExtractAll("INC123456 md5:d41d8cd98f00b204e9800998ecf8427e 900150983cd24fb0d6963f7d28e17f72 " +
+		"from 10.0.0.1 on www.corp.example, mail soc@corp.example")
+	if len(got["ticket"]) != 1 || got["ticket"][0].Value != "INC123456" {
+		t.Errorf("ticket = %v", got["ticket"])
+	}
+	if len(got["md5"]) != 1 || got["md5"][0].Value != "d41d8cd98f00b204e9800998ecf8427e" || got["md5"][0].Severity != "high" {
+		t.Errorf("md5 = %v", got["md5"])
+	}
+	if got["ipv4"] != nil || got["domain"] != nil || got["email"] != nil {
+		t.Errorf("ignored indicators extracted: %v", got)
+	}
+	r := &Result{Source: "gateway"}
+	c.ExtractInto("md5:d41d8cd98f00b204e9800998ecf8427e", r)
+	if md5 := r.Matches["md5"]; len(md5) != 1 || !slices.Equal(md5[0].Tags, []string{"hash", "email"}) || md5[0].TLP != TLPAmber {
+		t.Errorf("md5 from gateway = %+v", md5)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"typo.json":    `{"ignored_domain": ["corp.example"]}`,
+		"regex.json":   `{"expressions": {"bad": "("}}`,
+		"profile.json": `{"profiles": ["nope"]}`,
+		"conf.yaml":    `ignored_domains: [corp.example]`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("LoadConfig(%s) succeeded", name)
+		}
+	}
+	if _, err := LoadConfig(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("LoadConfig of a missing file succeeded")
+	}
+}
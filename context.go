@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// lineStarts returns the offset of the first byte of every line in text.
+func lineStarts(text string) []int {
+	starts := []int{0}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// position returns the 1-based line and rune column of an offset in the
+// original input, or zeros when line numbers are disabled.
+func (s source) position(offset int) (line, column int) {
+	if s.lines == nil {
+		return 0, 0
+	}
+	i := sort.SearchInts(s.lines, offset+1) - 1
+	return i + 1, utf8.RuneCountInString(s.orig[s.lines[i]:offset]) + 1
+}
+
+// matchContext returns the text surrounding text[start:end] according to
+// the ContextWindow and SentenceContext settings, or "" when both are off.
+func (c *Contextualizer) matchContext(text string, start, end int) string {
+	switch {
+	case c.SentenceContext:
+		return sentenceAround(text, start, end)
+	case c.ContextWindow > 0:
+		return windowAround(text, start, end, c.ContextWindow)
+	}
+	return ""
+}
+
+// windowAround returns text[start:end] widened by n bytes on each side,
+// adjusted outwards so no UTF-8 sequence is cut in half.
+func windowAround(text string, start, end, n int) string {
+	from, to := max(0, start-n), min(len(text), end+n)
+	for from > 0 && !utf8.RuneStart(text[from]) {
+		from--
+	}
+	for to < len(text) && !utf8.RuneStart(text[to]) {
+		to++
+	}
+	return text[from:to]
+}
+
+// sentenceAround returns the sentence (or line) containing text[start:end].
+// A sentence ends at a newline or at '.', '!' or '?' followed by whitespace,
+// so dots inside domains and IPs don't split it.
+func sentenceAround(text string, start, end int) string {
+	from := start
+	for from > 0 && !sentenceBreak(text, from-1) {
+		from--
+	}
+	to := end
+	for to < len(text) && !sentenceBreak(text, to) {
+		to++
+	}
+	if to < len(text) && text[to] != '\n' {
+		to++ // keep the terminating punctuation
+	}
+	return strings.TrimSpace(text[from:to])
+}
+
+func sentenceBreak(text string, i int) bool {
+	switch text[i] {
+	case '\n':
+		return true
+	case '.', '!', '?':
+		return i+1 == len(text) || text[i+1] == ' ' || text[i+1] == '\t' || text[i+1] == '\n' || text[i+1] == '\r'
+	}
+	return false
+}
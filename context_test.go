@@ -0,0 +1,47 @@
+package parser
+
+import "testing"
+
+func TestContextualizer_ContextWindow(t *testing.T) {
+	text := "Intro line. The dropper d41d8cd98f00b204e9800998ecf8427e was benign! Next one."
+
+	c := NewContextualizer(WithKinds("md5"), WithContextWindow(8))
+	got := c.ExtractAll(text)["md5"]
+	if len(got) != 1 || got[0].Context != "dropper d41d8cd98f00b204e9800998ecf8427e was ben" {
+		t.Errorf("window Context = %q", got[0].Context)
+	}
+
+	c = NewContextualizer(WithKinds("md5"), WithSentenceContext())
+	got = c.ExtractAll(text)["md5"]
+	if len(got) != 1 || got[0].Context != "The dropper d41d8cd98f00b204e9800998ecf8427e was benign!" {
+		t.Errorf("sentence Context = %q", got[0].Context)
+	}
+}
+
+func TestWindowAround_UTF8(t *testing.T) {
+	text := "é8.8.8.8é"
+	if got := windowAround(text, 2, 9, 1); got != text {
+		t.Errorf("windowAround() = %q, want %q", got, text)
+	}
+}
+
+func TestContextualizer_LineNumbers(t *testing.T) {
+	text := "first line\nsecond 8.8.8.8\n\tthird é 1.1.1.1"
+
+	c := NewContextualizer(WithKinds("ipv4"), WithLineNumbers())
+	got := c.GetMatches(text, "ipv4", c.Expressions["ipv4"])
+	if len(got) != 2 {
+		t.Fatalf("GetMatches() = %v", got)
+	}
+	if got[0].Line != 2 || got[0].Column != 8 {
+		t.Errorf("8.8.8.8 at %d:%d, want 2:8", got[0].Line, got[0].Column)
+	}
+	if got[1].Line != 3 || got[1].Column != 10 {
+		t.Errorf("1.1.1.1 at %d:%d, want 3:10", got[1].Line, got[1].Column)
+	}
+
+	c = NewContextualizer(WithKinds("ipv4"))
+	if got := c.GetMatches(text, "ipv4", c.Expressions["ipv4"]); got[0].Line != 0 {
+		t.Errorf("Line set without WithLineNumbers: %d", got[0].Line)
+	}
+}
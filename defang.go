@@ -0,0 +1,185 @@
+package parser
+
+import "strings"
+
+type fang struct{ defanged, fanged string }
+
+// fangs lists the defanging conventions recognized when MatchDefanged is
+// set, mapped to the text they stand for. Longer tokens come first so that
+// "[://]" wins over "[:]".
+var fangs = []fang{
+	{"hxxps", "https"},
+	{"hxxp", "http"},
+	{"fxp", "ftp"},
+	{"[://]", "://"},
+	{"[:]", ":"},
+	{"[/]", "/"},
+	{"[.]", "."},
+	{"(.)", "."},
+	{"{.}", "."},
+	{"[dot]", "."},
+	{"(dot)", "."},
+	{"{dot}", "."},
+	{"[@]", "@"},
+	{"[at]", "@"},
+	{"(at)", "@"},
+	{"{at}", "@"},
+}
+
+// source is the text handed to the expressions. When the input had to be
+// decoded or refanged, pos maps every byte of text back to its offset in
+// orig.
+type source struct {
+	text  string
+	orig  string
+	pos   []int
+	lines []int // line start offsets in orig, when line numbers are on
+	// checks are the ignore lists in effect for this extraction, and
+	// noDedup reports every occurrence of a value (see ScanFlag).
+	checks  *PrivateChecks
+	noDedup bool
+	// suppressed counts, per kind, the values dropped by checks, when
+	// non-nil.
+	suppressed map[string]int
+}
+
+func (c *Contextualizer) newSource(text string, flags ScanFlag) source {
+	text = c.preprocess(text)
+	src := source{text: text, orig: text, checks: c.checks(), noDedup: flags&NoDedup != 0}
+	if flags&NoIgnore != 0 {
+		src.checks = noChecks
+	}
+	for _, step := range c.rewrites() {
+		var pos []int
+		src.text, pos = step(src.text)
+		src.pos = composePos(src.pos, pos)
+	}
+	if c.LineNumbers {
+		src.lines = lineStarts(text)
+	}
+	return src
+}
+
+// raw returns the original text behind text[start:end], or "" when it is
+// identical to the refanged value.
+func (s source) raw(start, end int) string {
+	if s.pos == nil {
+		return ""
+	}
+	r := s.orig[s.pos[start]:s.pos[end]]
+	if r == s.text[start:end] {
+		return ""
+	}
+	return r
+}
+
+// Refang turns defanged indicators in text back into their usable form, for
+// example "hxxps://evil[.]com" into "https://evil.com".
+func Refang(text string) string {
+	refanged, _ := refang(text)
+	return refanged
+}
+
+// Defang makes indicators in text safe to paste into chat or tickets by
+// rewriting URL schemes (http to hxxp, ftp to fxp) and bracketing dots and
+// at signs, so "https://evil.com" becomes "hxxps://evil[.]com".
+func Defang(text string) string {
+	var b strings.Builder
+	b.Grow(len(text) + len(text)/4)
+
+	for i := 0; i < len(text); i++ {
+		if i == 0 || !isWordByte(text[i-1]) {
+			if scheme, ok := schemeAt(text, i); ok {
+				b.WriteString(scheme)
+				i += len(scheme) - 1
+				continue
+			}
+		}
+		switch ch := text[i]; {
+		case (ch == '.' || ch == '@') && !bracketed(text, i):
+			b.WriteByte('[')
+			b.WriteByte(ch)
+			b.WriteByte(']')
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
+
+// schemeFangs are the URL schemes rewritten by Defang.
+var schemeFangs = []fang{
+	{"hxxps://", "https://"},
+	{"hxxp://", "http://"},
+	{"fxp://", "ftp://"},
+}
+
+func schemeAt(text string, i int) (string, bool) {
+	for _, f := range schemeFangs {
+		if len(text)-i >= len(f.fanged) && strings.EqualFold(text[i:i+len(f.fanged)], f.fanged) {
+			return f.defanged, true
+		}
+	}
+	return "", false
+}
+
+func bracketed(text string, i int) bool {
+	return i > 0 && i+1 < len(text) && text[i-1] == '[' && text[i+1] == ']'
+}
+
+// offset maps a byte offset in text back to the original input.
+func (s source) offset(i int) int {
+	if s.pos == nil {
+		return i
+	}
+	return s.pos[i]
+}
+
+// refang replaces every defanging token in text and returns the result along
+// with the offset in text of each byte of the result. The offset slice has
+// one extra trailing entry so that end offsets map as well.
+func refang(text string) (string, []int) {
+	var b strings.Builder
+	b.Grow(len(text))
+	pos := make([]int, 0, len(text)+1)
+
+	for i := 0; i < len(text); {
+		token, ok := fangAt(text, i)
+		if !ok {
+			b.WriteByte(text[i])
+			pos = append(pos, i)
+			i++
+			continue
+		}
+		b.WriteString(token.fanged)
+		for range len(token.fanged) {
+			pos = append(pos, i)
+		}
+		i += len(token.defanged)
+	}
+	pos = append(pos, len(text))
+	return b.String(), pos
+}
+
+func fangAt(text string, i int) (fang, bool) {
+	switch text[i] {
+	case '[', '(', '{':
+	case 'h', 'H', 'f', 'F':
+		// Scheme tokens only count at the start of a word.
+		if i > 0 && isWordByte(text[i-1]) {
+			return fang{}, false
+		}
+	default:
+		return fang{}, false
+	}
+	for _, f := range fangs {
+		if len(text)-i >= len(f.defanged) && strings.EqualFold(text[i:i+len(f.defanged)], f.defanged) {
+			return f, true
+		}
+	}
+	return fang{}, false
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
@@ -0,0 +1,41 @@
+package parser
+
+import "testing"
+
+func TestRefangDefang(t *testing.T) {
+	tests := []struct {
+		fanged   string
+		defanged string
+	}{
+		{"https://evil.com/gate", "hxxps://evil[.]com/gate"},
+		{"ftp://files.example.org", "fxp://files[.]example[.]org"},
+		{"1.2.3.4", "1[.]2[.]3[.]4"},
+		{"bad@evil.org", "bad[@]evil[.]org"},
+	}
+
+	for _, tt := range tests {
+		if got := Defang(tt.fanged); got != tt.defanged {
+			t.Errorf("Defang(%q) = %q, want %q", tt.fanged, got, tt.defanged)
+		}
+		if got := Refang(tt.defanged); got != tt.fanged {
+			t.Errorf("Refang(%q) = %q, want %q", tt.defanged, got, tt.fanged)
+		}
+		if got := Defang(tt.defanged); got != tt.defanged {
+			t.Errorf("Defang(%q) is not idempotent: %q", tt.defanged, got)
+		}
+	}
+
+	if got := Refang("evil(dot)com and hXXp://x[.]y"); got != "evil.com and http://x.y" {
+		t.Errorf("Refang() = %q", got)
+	}
+}
+
+func TestContextualizer_DefangOutput(t *testing.T) {
+	c := NewContextualizer()
+	c.DefangOutput = true
+
+	results := c.ExtractAll("Beacon to http://evil.com/a")
+	if len(results["url"]) != 1 || results["url"][0].Value != "hxxp://evil[.]com/a" {
+		t.Errorf("URL not defanged: %v", results["url"])
+	}
+}
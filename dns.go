@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// HostResolver resolves host names to addresses. *net.Resolver satisfies
+// it.
+type HostResolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// DNSEnricher is an Enricher that resolves domain matches to their A and
+// AAAA records. It attaches the addresses as "dns.a" and "dns.aaaa"
+// metadata, comma separated, and emits each of them as a related ipv4 or
+// ipv6 match. Domains that don't exist are not an error.
+type DNSEnricher struct {
+	Resolver HostResolver
+}
+
+// NewDNSEnricher returns a DNSEnricher using r.
+func NewDNSEnricher(r HostResolver) *DNSEnricher {
+	return &DNSEnricher{Resolver: r}
+}
+
+// Name implements Enricher.
+func (*DNSEnricher) Name() string { return "dns" }
+
+// Enrich implements Enricher.
+func (d *DNSEnricher) Enrich(ctx context.Context, m Match) (Enrichment, error) {
+	if m.Type != "domain" {
+		return Enrichment{}, nil
+	}
+	host := m.Normalized
+	if host == "" {
+		host = m.Value
+	}
+	addrs, err := d.Resolver.LookupNetIP(ctx, "ip", Refang(host))
+	if dnsErr := (*net.DNSError)(nil); errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return Enrichment{}, nil
+	}
+	if err != nil {
+		return Enrichment{}, err
+	}
+
+	var out Enrichment
+	var v4, v6 []string
+	for _, addr := range addrs {
+		addr = addr.Unmap()
+		kind := "ipv6"
+		if addr.Is4() {
+			kind = "ipv4"
+			v4 = append(v4, addr.String())
+		} else {
+			v6 = append(v6, addr.String())
+		}
+		out.Related = append(out.Related, Match{Value: addr.String(), Type: kind})
+	}
+	if len(addrs) > 0 {
+		out.Metadata = make(map[string]string, 2)
+	}
+	if len(v4) > 0 {
+		out.Metadata["a"] = strings.Join(v4, ",")
+	}
+	if len(v6) > 0 {
+		out.Metadata["aaaa"] = strings.Join(v6, ",")
+	}
+	return out, nil
+}
@@ -0,0 +1,44 @@
+package parser
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+)
+
+type fakeResolver map[string][]netip.Addr
+
+func (f fakeResolver) LookupNetIP(_ context.Context, _, host string) ([]netip.Addr, error) {
+	addrs, ok := f[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addrs, nil
+}
+
+func TestDNSEnricher(t *testing.T) {
+	resolver := fakeResolver{
+		"evil.example": {netip.MustParseAddr("::ffff:192.0.2.10"), netip.MustParseAddr("2001:db8::10")},
+	}
+	c := NewContextualizer(WithEnricher(NewDNSEnricher(resolver), 0))
+	r := NewResult()
+	c.ExtractInto("resolve evil.example and gone.example", r)
+
+	if err := c.Enrich(context.Background(), r); err != nil {
+		t.Fatalf("Enrich() error = %v", err)
+	}
+	evil := r.Matches["domain"][0]
+	if evil.Metadata["dns.a"] != "192.0.2.10" || evil.Metadata["dns.aaaa"] != "2001:db8::10" {
+		t.Errorf("Metadata = %v", evil.Metadata)
+	}
+	if v4 := r.Matches["ipv4"]; len(v4) != 1 || v4[0].Value != "192.0.2.10" || v4[0].Parent != "evil.example" {
+		t.Errorf("ipv4 = %+v", v4)
+	}
+	if v6 := r.Matches["ipv6"]; len(v6) != 1 || v6[0].Value != "2001:db8::10" {
+		t.Errorf("ipv6 = %+v", v6)
+	}
+	if gone := r.Matches["domain"][1]; gone.Metadata != nil {
+		t.Errorf("gone.example Metadata = %v", gone.Metadata)
+	}
+}
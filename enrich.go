@@ -0,0 +1,131 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// defaultEnrichWorkers bounds the concurrent Enrich calls when
+// EnrichWorkers is not set.
+const defaultEnrichWorkers = 8
+
+// Enricher looks up context for a match after extraction, such as the
+// addresses a domain resolves to or the country of an IP. Enrich is called
+// once per match, concurrently with other calls, and should return an
+// empty Enrichment quickly for kinds it doesn't handle.
+type Enricher interface {
+	// Name identifies the enricher; it prefixes the metadata keys it adds
+	// and the errors it returns.
+	Name() string
+	Enrich(ctx context.Context, m Match) (Enrichment, error)
+}
+
+// Enrichment is what an Enricher found about a match.
+type Enrichment struct {
+	// Metadata is added to Match.Metadata, each key prefixed with the
+	// enricher's name and a dot.
+	Metadata map[string]string
+	// Related are new matches linked to the enriched one, such as the
+	// addresses a domain resolves to. They are added to the Result with
+	// Parent set to the enriched match and its offsets.
+	Related []Match
+}
+
+type enricher struct {
+	Enricher
+	timeout time.Duration
+}
+
+// AddEnricher registers e to run on every match passed to Enrich. Each
+// call to e is cancelled after timeout, unless it is zero.
+func (c *Contextualizer) AddEnricher(e Enricher, timeout time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.enrichers = append(c.enrichers, enricher{Enricher: e, timeout: timeout})
+}
+
+// Enrich runs the registered enrichers over the matches of r, at most
+// EnrichWorkers calls at a time, and adds what they find to r. It returns
+// the errors of the failed calls joined together; the other results are
+// applied regardless. Results are applied in a fixed order once every
+// call has returned, so r must not be used concurrently with Enrich.
+func (c *Contextualizer) Enrich(ctx context.Context, r *Result) error {
+	c.mu.RLock()
+	enrichers := slices.Clone(c.enrichers)
+	c.mu.RUnlock()
+	if len(enrichers) == 0 {
+		return nil
+	}
+
+	type job struct {
+		typ string
+		i   int
+		e   enricher
+		out Enrichment
+		err error
+	}
+	var jobs []*job
+	for _, typ := range slices.Sorted(maps.Keys(r.Matches)) {
+		for i := range r.Matches[typ] {
+			for _, e := range enrichers {
+				jobs = append(jobs, &job{typ: typ, i: i, e: e})
+			}
+		}
+	}
+
+	workers := c.EnrichWorkers
+	if workers <= 0 {
+		workers = defaultEnrichWorkers
+	}
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for _, j := range jobs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			j.err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			ctx := ctx
+			if j.e.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, j.e.timeout)
+				defer cancel()
+			}
+			j.out, j.err = j.e.Enrich(ctx, r.Matches[j.typ][j.i])
+		}()
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, j := range jobs {
+		m := &r.Matches[j.typ][j.i]
+		if j.err != nil {
+			errs = append(errs, fmt.Errorf("enricher %s on %s %q: %w", j.e.Name(), m.Type, m.Value, j.err))
+			continue
+		}
+		for key, val := range j.out.Metadata {
+			if m.Metadata == nil {
+				m.Metadata = make(map[string]string, len(j.out.Metadata))
+			}
+			m.Metadata[j.e.Name()+"."+key] = val
+		}
+		parent := *m
+		for _, rel := range j.out.Related {
+			rel.Parent = parent.Value
+			rel.Start, rel.End = parent.Start, parent.End
+			rel.Line, rel.Column = parent.Line, parent.Column
+			r.add(rel)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeEnricher struct {
+	running, peak atomic.Int32
+}
+
+func (*fakeEnricher) Name() string { return "fake" }
+
+func (f *fakeEnricher) Enrich(ctx context.Context, m Match) (Enrichment, error) {
+	n := f.running.Add(1)
+	defer f.running.Add(-1)
+	for p := f.peak.Load(); n > p && !f.peak.CompareAndSwap(p, n); p = f.peak.Load() {
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	switch {
+	case m.Type != "domain":
+		return Enrichment{}, nil
+	case strings.HasPrefix(m.Value, "slow."):
+		<-ctx.Done()
+		return Enrichment{}, ctx.Err()
+	}
+	return Enrichment{
+		Metadata: map[string]string{"registrar": "Example Registrar"},
+		Related:  []Match{{Value: "192.0.2.1", Type: "ipv4"}},
+	}, nil
+}
+
+func TestContextualizer_Enrich(t *testing.T) {
+	f := &fakeEnricher{}
+	c := NewContextualizer(WithEnricher(f, 20*time.Millisecond))
+	c.EnrichWorkers = 2
+	r := NewResult()
+	c.ExtractInto("hosts evil.example, slow.example, a.org, b.org, c.org and 10.0.0.1", r)
+
+	err := c.Enrich(context.Background(), r)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "slow.example") {
+		t.Errorf("Enrich() error = %v", err)
+	}
+	if p := f.peak.Load(); p > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", p)
+	}
+
+	domains := r.Matches["domain"]
+	if domains[0].Value != "evil.example" || domains[0].Metadata["fake.registrar"] != "Example Registrar" {
+		t.Errorf("domain = %+v", domains[0])
+	}
+	var related int
+	for _, m := range r.Matches["ipv4"] {
+		if m.Value == "192.0.2.1" {
+			related++
+			if m.Parent == "" || m.Start == 0 {
+				t.Errorf("related = %+v", m)
+			}
+		}
+	}
+	if related != 4 {
+		t.Errorf("related ipv4 = %d, want 4", related)
+	}
+}
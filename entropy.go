@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+const defaultEntropyCharset = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/=_-"
+
+// EntropyConfig configures the generic secret detector, which flags runs of
+// high-entropy text that no other kind recognized as "secret_candidate"
+// matches. Zero fields fall back to the defaults noted below.
+type EntropyConfig struct {
+	// Charset lists the bytes a candidate may consist of. Defaults to the
+	// base64 and base64url alphabets.
+	Charset string `json:"charset,omitempty"`
+	// MinLength is the shortest run considered. Defaults to 20.
+	MinLength int `json:"min_length,omitempty"`
+	// Threshold is the minimum Shannon entropy in bits per byte. Defaults
+	// to 4.0.
+	Threshold float64 `json:"threshold,omitempty"`
+}
+
+// find returns the secret candidates in text that are not already part of a
+// match in known.
+func (e *EntropyConfig) find(text string, known []Match) []Match {
+	charset := e.Charset
+	if charset == "" {
+		charset = defaultEntropyCharset
+	}
+	minLength := e.MinLength
+	if minLength == 0 {
+		minLength = 20
+	}
+	threshold := e.Threshold
+	if threshold == 0 {
+		threshold = 4.0
+	}
+
+	var results []Match
+	seen := getSeen()
+	defer putSeen(seen)
+	for start := 0; start < len(text); {
+		if !strings.ContainsRune(charset, rune(text[start])) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(text) && strings.ContainsRune(charset, rune(text[end])) {
+			end++
+		}
+		// Keep trailing base64 padding but drop "key=" style prefixes.
+		runStart := start + strings.LastIndexByte(strings.TrimRight(text[start:end], "="), '=') + 1
+		run := text[runStart:end]
+		start = end
+
+		if len(run) < minLength || seen[run] || isKnown(run, known) {
+			continue
+		}
+		seen[run] = true
+		if h := shannonEntropy(run); h >= threshold {
+			results = append(results, Match{
+				Value:    run,
+				Type:     "secret_candidate",
+				Start:    runStart,
+				End:      end,
+				Metadata: map[string]string{"entropy": strconv.FormatFloat(h, 'f', 2, 64)},
+			})
+		}
+	}
+	return results
+}
+
+func isKnown(value string, known []Match) bool {
+	for _, m := range known {
+		if strings.Contains(m.Value, value) || strings.Contains(value, m.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// shannonEntropy returns the entropy of s in bits per byte.
+func shannonEntropy(s string) float64 {
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+	var h float64
+	for _, n := range counts {
+		if n == 0 {
+			continue
+		}
+		p := float64(n) / float64(len(s))
+		h -= p * math.Log2(p)
+	}
+	return h
+}
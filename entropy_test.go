@@ -0,0 +1,39 @@
+package parser
+
+import "testing"
+
+func TestContextualizer_Entropy(t *testing.T) {
+	c := NewContextualizer()
+	c.Entropy = &EntropyConfig{}
+	text := "token=Zm9vYmFyYmF6cXV4X2tleV9kYXRhXzE4MjM0NTY3 hash d41d8cd98f00b204e9800998ecf8427e " +
+		"and some internationalization aaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+
+	results := c.ExtractAll(text)
+
+	got := results["secret_candidate"]
+	if len(got) != 1 || got[0].Value != "Zm9vYmFyYmF6cXV4X2tleV9kYXRhXzE4MjM0NTY3" {
+		t.Fatalf("secret_candidate extraction failed: %v", got)
+	}
+	if got[0].Metadata["entropy"] == "" {
+		t.Errorf("entropy missing from metadata")
+	}
+}
+
+func TestShannonEntropy(t *testing.T) {
+	if h := shannonEntropy("aaaa"); h != 0 {
+		t.Errorf("shannonEntropy(aaaa) = %v, want 0", h)
+	}
+	if h := shannonEntropy("abcd"); h != 2 {
+		t.Errorf("shannonEntropy(abcd) = %v, want 2", h)
+	}
+}
+
+func TestContextualizer_EntropyOffsets(t *testing.T) {
+	c := NewContextualizer(WithEntropy(EntropyConfig{}))
+	text := "token=Zm9vYmFyYmF6cXV4X2tleV9kYXRhXzE4MjM0NTY3"
+
+	got := c.ExtractAll(text)["secret_candidate"]
+	if len(got) != 1 || text[got[0].Start:got[0].End] != got[0].Value {
+		t.Errorf("secret_candidate offsets wrong: %v", got)
+	}
+}
@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"html"
+	"strings"
+)
+
+// decodeEscapes replaces percent escapes of printable ASCII characters and
+// HTML character references in text with the characters they stand for,
+// and returns the result along with the offset in text of each of its
+// bytes, in the form refang uses.
+func decodeEscapes(text string) (string, []int) {
+	var b strings.Builder
+	b.Grow(len(text))
+	pos := make([]int, 0, len(text)+1)
+
+	for i := 0; i < len(text); {
+		decoded, n := escapeAt(text, i)
+		if n == 0 {
+			b.WriteByte(text[i])
+			pos = append(pos, i)
+			i++
+			continue
+		}
+		b.WriteString(decoded)
+		for range len(decoded) {
+			pos = append(pos, i)
+		}
+		i += n
+	}
+	pos = append(pos, len(text))
+	return b.String(), pos
+}
+
+// escapeAt decodes the escape starting at text[i], if any, and returns it
+// along with the length of the escape.
+func escapeAt(text string, i int) (string, int) {
+	switch text[i] {
+	case '%':
+		if i+2 < len(text) && isHexByte(text[i+1]) && isHexByte(text[i+2]) {
+			// Escaped control characters and non-ASCII bytes are left
+			// alone, so decoding never produces invalid text.
+			if ch := unhex(text[i+1])<<4 | unhex(text[i+2]); ch >= 0x20 && ch < 0x7f {
+				return string(ch), 3
+			}
+		}
+	case '&':
+		// The longest reference worth decoding is about "&#x0002f;".
+		end := strings.IndexByte(text[i:min(len(text), i+10)], ';')
+		if end < 2 {
+			break
+		}
+		ref := text[i : i+end+1]
+		if decoded := html.UnescapeString(ref); decoded != ref {
+			return decoded, len(ref)
+		}
+	}
+	return "", 0
+}
+
+// composePos maps offsets through two successive rewrites: inner maps the
+// final text to an intermediate one, which outer maps to the original.
+// Either may be nil for a rewrite that changed nothing.
+func composePos(outer, inner []int) []int {
+	if outer == nil {
+		return inner
+	}
+	if inner == nil {
+		return outer
+	}
+	pos := make([]int, len(inner))
+	for i, p := range inner {
+		pos[i] = outer[p]
+	}
+	return pos
+}
@@ -0,0 +1,41 @@
+package parser
+
+import "testing"
+
+func TestDecodeEscapes(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"u=https%3A%2F%2Fevil.com%2Fx", "u=https://evil.com/x"},
+		{"a&amp;b &#x2f; &#47; &lt;", "a&b / / <"},
+		{"100% sure, %zz, %00 and %e9", "100% sure, %zz, %00 and %e9"},
+		{"AT&T; & co", "AT&T; & co"},
+	}
+	for _, tt := range tests {
+		got, pos := decodeEscapes(tt.in)
+		if got != tt.want {
+			t.Errorf("decodeEscapes(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if len(pos) != len(got)+1 || pos[len(got)] != len(tt.in) {
+			t.Errorf("decodeEscapes(%q) offsets = %v", tt.in, pos)
+		}
+	}
+}
+
+func TestContextualizer_DecodeEscapes(t *testing.T) {
+	text := `<a href="/redirect?to=evil%2Eexample%2Ecom&amp;ref=1">mail bob&#64;phish.example</a>`
+
+	c := NewContextualizer(WithEscapeDecoding())
+	got := c.ExtractAll(text)
+	if d := got["domain"]; len(d) != 1 || d[0].Value != "evil.example.com" || text[d[0].Start:d[0].End] != "evil%2Eexample%2Ecom" || d[0].Raw != "evil%2Eexample%2Ecom" {
+		t.Errorf("domain = %+v", d)
+	}
+	if e := got["email"]; len(e) != 1 || e[0].Value != "bob@phish.example" {
+		t.Errorf("email = %+v", e)
+	}
+
+	c = NewContextualizer(WithEscapeDecoding(), WithDefanged())
+	if d := c.ExtractAll("host evil%5B.%5Dexample.com")["domain"]; len(d) != 1 || d[0].Value != "evil.example.com" || d[0].End != len("host evil%5B.%5Dexample.com") {
+		t.Errorf("escaped and defanged domain = %+v", d)
+	}
+}
@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"encoding/hex"
+	"math/bits"
+	"strings"
+)
+
+// validEIP55 reports whether a 0x-prefixed address satisfies its EIP-55
+// mixed-case checksum. All-lowercase and all-uppercase addresses carry no
+// checksum and are accepted.
+func validEIP55(addr string) bool {
+	hexPart := strings.TrimPrefix(strings.TrimPrefix(addr, "0x"), "0X")
+	lower := strings.ToLower(hexPart)
+	if hexPart == lower || hexPart == strings.ToUpper(hexPart) {
+		return true
+	}
+
+	hash := hex.EncodeToString(keccak256([]byte(lower)))
+	for i := 0; i < len(hexPart); i++ {
+		ch := hexPart[i]
+		if ch <= '9' {
+			continue
+		}
+		// Letters must be uppercase exactly when the hash nibble is >= 8.
+		if (hash[i] >= '8') != (ch <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+var keccakRoundConstants = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808a, 0x8000000080008000,
+	0x000000000000808b, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008a, 0x0000000000000088, 0x0000000080008009, 0x000000008000000a,
+	0x000000008000808b, 0x800000000000008b, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800a, 0x800000008000000a,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+var keccakRotations = [25]int{
+	0, 1, 62, 28, 27,
+	36, 44, 6, 55, 20,
+	3, 10, 43, 25, 39,
+	41, 45, 15, 21, 8,
+	18, 2, 61, 56, 14,
+}
+
+// keccak256 is the original Keccak-256 (pre-FIPS padding) used by Ethereum.
+func keccak256(data []byte) []byte {
+	const rate = 136
+	var state [25]uint64
+
+	padded := make([]byte, len(data), len(data)+rate)
+	copy(padded, data)
+	padded = append(padded, 0x01)
+	for len(padded)%rate != 0 {
+		padded = append(padded, 0)
+	}
+	padded[len(padded)-1] |= 0x80
+
+	for off := 0; off < len(padded); off += rate {
+		for i := 0; i < rate/8; i++ {
+			var lane uint64
+			for b := 0; b < 8; b++ {
+				lane |= uint64(padded[off+i*8+b]) << (8 * b)
+			}
+			state[i] ^= lane
+		}
+		keccakF1600(&state)
+	}
+
+	out := make([]byte, 32)
+	for i := 0; i < 4; i++ {
+		for b := 0; b < 8; b++ {
+			out[i*8+b] = byte(state[i] >> (8 * b))
+		}
+	}
+	return out
+}
+
+func keccakF1600(a *[25]uint64) {
+	var c [5]uint64
+	var b [25]uint64
+	for round := 0; round < 24; round++ {
+		for x := 0; x < 5; x++ {
+			c[x] = a[x] ^ a[x+5] ^ a[x+10] ^ a[x+15] ^ a[x+20]
+		}
+		for x := 0; x < 5; x++ {
+			d := c[(x+4)%5] ^ bits.RotateLeft64(c[(x+1)%5], 1)
+			for y := 0; y < 25; y += 5 {
+				a[y+x] ^= d
+			}
+		}
+		for x := 0; x < 5; x++ {
+			for y := 0; y < 5; y++ {
+				b[y+5*((2*x+3*y)%5)] = bits.RotateLeft64(a[x+5*y], keccakRotations[x+5*y])
+			}
+		}
+		for y := 0; y < 25; y += 5 {
+			for x := 0; x < 5; x++ {
+				a[y+x] = b[y+x] ^ (^b[y+(x+1)%5] & b[y+(x+2)%5])
+			}
+		}
+		a[0] ^= keccakRoundConstants[round]
+	}
+}
@@ -0,0 +1,36 @@
+package parser
+
+import "testing"
+
+func TestValidEIP55(t *testing.T) {
+	tests := []struct {
+		addr  string
+		valid bool
+	}{
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", true},
+		{"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359", true},
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", true},
+		{"0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED", true},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", false},
+	}
+
+	for _, tt := range tests {
+		if got := validEIP55(tt.addr); got != tt.valid {
+			t.Errorf("validEIP55(%q) = %v, want %v", tt.addr, got, tt.valid)
+		}
+	}
+}
+
+func TestContextualizer_Ethereum(t *testing.T) {
+	c := NewContextualizer()
+	c.VerifyEIP55 = true
+	text := "Send to 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD or 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+
+	results := c.ExtractAll(text)
+	if len(results["eth"]) != 1 || results["eth"][0].Value != "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed" {
+		t.Errorf("ETH extraction failed: %v", results["eth"])
+	}
+	if len(results["sha1"]) != 0 {
+		t.Errorf("ETH address also reported as sha1: %v", results["sha1"])
+	}
+}
@@ -0,0 +1,172 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// AddExpression compiles pattern and registers it under kind, replacing any
+// existing expression of that kind. It is safe to call while extractions
+// are running; they keep using the expressions they started with.
+func (c *Contextualizer) AddExpression(kind, pattern string) error {
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("compiling expression %q: %w", kind, err)
+	}
+	c.SetExpression(kind, regex)
+	return nil
+}
+
+// SetExpression registers an already compiled expression under kind,
+// replacing any existing expression of that kind.
+func (c *Contextualizer) SetExpression(kind string, regex *regexp.Regexp) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.extractors, kind)
+	c.Expressions[kind] = regex
+	c.changeExpression(kind, regex)
+}
+
+// RemoveExpression deletes the expression or Extractor registered under
+// kind and reports whether there was one.
+func (c *Contextualizer) RemoveExpression(kind string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, isExpr := c.Expressions[kind]
+	_, isExtractor := c.extractors[kind]
+	delete(c.Expressions, kind)
+	delete(c.extractors, kind)
+	c.changeExpression(kind, nil)
+	return isExpr || isExtractor
+}
+
+// DisableKind stops extractions from running the expression registered
+// under kind without removing it, so EnableKind can turn it back on.
+func (c *Contextualizer) DisableKind(kind string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.disabled == nil {
+		c.disabled = make(map[string]struct{})
+	}
+	c.disabled[kind] = struct{}{}
+	c.changeKind(kind, true)
+}
+
+// EnableKind reverses DisableKind.
+func (c *Contextualizer) EnableKind(kind string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.disabled, kind)
+	c.changeKind(kind, false)
+}
+
+// changeExpression records that the expression of kind was set to regex,
+// or removed when it is nil, for Reload. The caller holds c.mu.
+func (c *Contextualizer) changeExpression(kind string, regex *regexp.Regexp) {
+	if c.exprChanges == nil {
+		c.exprChanges = make(map[string]*regexp.Regexp)
+	}
+	c.exprChanges[kind] = regex
+}
+
+// changeKind records that kind was disabled or enabled, for Reload. The
+// caller holds c.mu.
+func (c *Contextualizer) changeKind(kind string, disabled bool) {
+	if c.kindChanges == nil {
+		c.kindChanges = make(map[string]bool)
+	}
+	c.kindChanges[kind] = disabled
+}
+
+// replayChanges applies the expression and kind changes recorded on c to
+// exprs, prefilters and disabled, which belong to a freshly loaded
+// config. The caller holds c.mu.
+func (c *Contextualizer) replayChanges(exprs map[string]*regexp.Regexp, prefilters map[*regexp.Regexp]prefilter, disabled map[string]struct{}) map[string]struct{} {
+	for kind, regex := range c.exprChanges {
+		if regex == nil {
+			delete(exprs, kind)
+			continue
+		}
+		exprs[kind] = regex
+		if pf, ok := c.prefilters[regex]; ok {
+			prefilters[regex] = pf
+		}
+	}
+	for kind, off := range c.kindChanges {
+		if !off {
+			delete(disabled, kind)
+			continue
+		}
+		if disabled == nil {
+			disabled = make(map[string]struct{})
+		}
+		disabled[kind] = struct{}{}
+	}
+	return disabled
+}
+
+// KindEnabled reports whether kind has an expression and is not disabled.
+func (c *Contextualizer) KindEnabled(kind string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, registered := c.Expressions[kind]
+	_, disabled := c.disabled[kind]
+	return registered && !disabled
+}
+
+// Kinds returns the enabled expression kinds in sorted order.
+func (c *Contextualizer) Kinds() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	kinds := make([]string, 0, len(c.Expressions)+len(c.extractors))
+	for kind := range c.Expressions {
+		if _, off := c.disabled[kind]; !off {
+			kinds = append(kinds, kind)
+		}
+	}
+	for kind := range c.extractors {
+		if _, off := c.disabled[kind]; !off {
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// expressions returns a snapshot of the enabled expressions that can be
+// used without holding the lock.
+func (c *Contextualizer) expressions() map[string]*regexp.Regexp {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	exprs := make(map[string]*regexp.Regexp, len(c.Expressions)+len(c.extractors))
+	for kind, regex := range c.Expressions {
+		if _, off := c.disabled[kind]; !off {
+			exprs[kind] = regex
+		}
+	}
+	// Extractors are scanned in place of a nil expression.
+	for kind := range c.extractors {
+		if _, off := c.disabled[kind]; !off {
+			exprs[kind] = nil
+		}
+	}
+	return exprs
+}
+
+func (c *Contextualizer) registered(kind string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.Expressions[kind]
+	if !ok {
+		_, ok = c.extractors[kind]
+	}
+	return ok
+}
+
+func (c *Contextualizer) nationalID(kind string) (NationalID, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	id, ok := c.nationalIDs[kind]
+	return id, ok
+}
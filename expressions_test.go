@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestContextualizer_ExpressionRegistry(t *testing.T) {
+	c := NewContextualizer(WithKinds("ipv4", "md5"))
+
+	if err := c.AddExpression("ticket", `\b(INC\d{6})\b`); err != nil {
+		t.Fatalf("AddExpression() error = %v", err)
+	}
+	if err := c.AddExpression("broken", `(`); err == nil {
+		t.Errorf("AddExpression() accepted an invalid pattern")
+	}
+	if !c.RemoveExpression("md5") || c.RemoveExpression("md5") {
+		t.Errorf("RemoveExpression() did not report removal correctly")
+	}
+	if got, want := c.Kinds(), []string{"ipv4", "ticket"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Kinds() = %v, want %v", got, want)
+	}
+}
+
+func TestContextualizer_ExpressionRegistryConcurrent(t *testing.T) {
+	c := NewContextualizer()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			c.ExtractAll("INC000001 from 8.8.8.8")
+		}()
+		go func() {
+			defer wg.Done()
+			_ = c.AddExpression("ticket", `\b(INC\d{6})\b`)
+			c.RemoveExpression("ticket")
+		}()
+	}
+	wg.Wait()
+}
+
+func TestContextualizer_ExtractKinds(t *testing.T) {
+	c := NewContextualizer()
+	text := "Hash d41d8cd98f00b204e9800998ecf8427e from 8.8.8.8 via evil.com"
+
+	results, err := c.ExtractKinds(text, "md5", "ipv4")
+	if err != nil {
+		t.Fatalf("ExtractKinds() error = %v", err)
+	}
+	if len(results) != 2 || len(results["md5"]) != 1 || len(results["ipv4"]) != 1 {
+		t.Errorf("ExtractKinds() = %v", results)
+	}
+
+	if _, err := c.ExtractKinds(text, "md5", "nope"); err == nil {
+		t.Errorf("ExtractKinds() accepted an unknown kind")
+	}
+}
+
+func TestContextualizer_DisableKind(t *testing.T) {
+	c := NewContextualizer(WithDisabledKinds("filepath", "filename"))
+	text := "Dropped evil/loader.bin from 8.8.8.8"
+
+	if c.KindEnabled("filepath") || !c.KindEnabled("ipv4") || c.Expressions["filepath"] == nil {
+		t.Fatalf("filepath enabled = %v, expression kept = %v", c.KindEnabled("filepath"), c.Expressions["filepath"] != nil)
+	}
+	if got := c.ExtractAll(text); got["filepath"] != nil || len(got["ipv4"]) != 1 {
+		t.Errorf("ExtractAll() = %v", got)
+	}
+	if _, err := c.ExtractKinds(text, "filepath"); err == nil {
+		t.Error("ExtractKinds() ran a disabled kind")
+	}
+
+	c.EnableKind("filepath")
+	if got := c.ExtractAll(text)["filepath"]; len(got) != 1 {
+		t.Errorf("filepath after EnableKind = %v", got)
+	}
+	c.DisableKind("ipv4")
+	for _, kind := range c.Kinds() {
+		if kind == "ipv4" || kind == "filename" {
+			t.Errorf("Kinds() lists disabled %s", kind)
+		}
+	}
+}
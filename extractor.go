@@ -0,0 +1,59 @@
+package parser
+
+// Extractor finds matches of one kind without a regular expression, for
+// detectors that need real parsing. Find returns the matches in text with
+// their Start and End offsets into it; Value defaults to text[Start:End]
+// and Metadata, when set, is kept. Type is ignored in favour of Kind.
+//
+// The matches go through the same ignore lists, validators, overlap
+// resolution and deduplication as those of the expressions.
+type Extractor interface {
+	Kind() string
+	Find(text string) []Match
+}
+
+// RegisterExtractor adds e under its kind, replacing any expression or
+// extractor of the same kind.
+func (c *Contextualizer) RegisterExtractor(e Extractor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.extractors == nil {
+		c.extractors = make(map[string]Extractor)
+	}
+	delete(c.Expressions, e.Kind())
+	c.changeExpression(e.Kind(), nil)
+	c.extractors[e.Kind()] = e
+}
+
+func (c *Contextualizer) extractor(kind string) (Extractor, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	e, ok := c.extractors[kind]
+	return e, ok
+}
+
+// scanExtractor runs the extractor registered under kind, claiming the
+// spans of its accepted matches in res, and reports whether yield wants
+// more matches.
+func (c *Contextualizer) scanExtractor(src source, kind string, res *resolver, yield func(Match) bool) bool {
+	e, ok := c.extractor(kind)
+	if !ok {
+		return true
+	}
+	seen := getSeen()
+	defer putSeen(seen)
+
+	for _, found := range e.Find(src.text) {
+		if found.Start < 0 || found.End < found.Start || found.End > len(src.text) {
+			continue
+		}
+		val := found.Value
+		if val == "" {
+			val = src.text[found.Start:found.End]
+		}
+		if !c.consider(src, kind, val, found.Start, found.End, found.Metadata, seen, res, yield) {
+			return false
+		}
+	}
+	return true
+}
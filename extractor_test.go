@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"net/netip"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// addrExtractor finds whitespace-separated IP addresses by parsing them.
+type addrExtractor struct{}
+
+func (addrExtractor) Kind() string { return "addr" }
+
+func (addrExtractor) Find(text string) []Match {
+	var out []Match
+	for i := 0; i < len(text); {
+		end := strings.IndexAny(text[i:], " \n")
+		if end < 0 {
+			end = len(text) - i
+		}
+		if addr, err := netip.ParseAddr(text[i : i+end]); err == nil {
+			family := "4"
+			if addr.Is6() {
+				family = "6"
+			}
+			out = append(out, Match{Start: i, End: i + end, Metadata: map[string]string{"family": family}})
+		}
+		i += end + 1
+	}
+	return out
+}
+
+func TestContextualizer_RegisterExtractor(t *testing.T) {
+	c := NewContextualizer(WithKinds("ipv4"))
+	c.RegisterExtractor(addrExtractor{})
+	if err := c.AddIgnorePattern("addr", `^10\.`); err != nil {
+		t.Fatal(err)
+	}
+
+	text := "from 192.0.2.7 and 2001:db8::1 and 10.0.0.1 and 192.0.2.7 again"
+	got := c.ExtractAll(text)
+	addrs := got["addr"]
+	if len(addrs) != 2 || addrs[0].Value != "192.0.2.7" || addrs[1].Metadata["family"] != "6" {
+		t.Fatalf("addr = %+v", addrs)
+	}
+	if text[addrs[1].Start:addrs[1].End] != "2001:db8::1" {
+		t.Errorf("addr offsets = %d:%d", addrs[1].Start, addrs[1].End)
+	}
+	if !slices.Contains(c.Kinds(), "addr") {
+		t.Errorf("Kinds() = %v", c.Kinds())
+	}
+
+	only, err := c.ExtractKinds(text, "addr")
+	if err != nil || len(only) != 1 || len(only["addr"]) != 2 {
+		t.Errorf("ExtractKinds() = %v, %v", only, err)
+	}
+
+	c.DisableKind("addr")
+	if got := c.ExtractAll(text); got["addr"] != nil {
+		t.Errorf("disabled addr = %+v", got["addr"])
+	}
+	c.EnableKind("addr")
+	if !c.RemoveExpression("addr") || c.ExtractAll(text)["addr"] != nil {
+		t.Error("RemoveExpression did not remove the extractor")
+	}
+}
@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	// defaultChunkSize and defaultChunkOverlap are used by ExtractReader
+	// when ChunkSize is not set.
+	defaultChunkSize    = 4 << 20
+	defaultChunkOverlap = 4 << 10
+)
+
+// ExtractFile is ExtractReader over the file at path.
+func (c *Contextualizer) ExtractFile(path string) (*Result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("extracting file: %w", err)
+	}
+	defer f.Close()
+	return c.ExtractReader(f)
+}
+
+// ExtractReader extracts the indicators of everything read from rd while
+// holding at most ChunkSize plus ChunkOverlap bytes of it, so multi-gigabyte
+// files can be scanned with bounded memory. Each chunk is scanned along with
+// the overlap that follows it, so an indicator straddling the edge is found
+// whole as long as it is shorter than the overlap. Chunks are cut at a line
+// break, or failing that at a space, within the overlap. Offsets and line
+// numbers refer to the whole input; Limits apply to each chunk. On a read
+// error, the matches found so far are returned with it.
+func (c *Contextualizer) ExtractReader(rd io.Reader) (*Result, error) {
+	size, overlap := c.chunking()
+	res := NewResult()
+	buf := make([]byte, 0, size+overlap)
+	chunk := NewResult()
+	seen := make(map[string]bool)
+	var base, lines int
+	for {
+		n, err := io.ReadFull(rd, buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		last := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+		if err != nil && !last {
+			return res, fmt.Errorf("extracting reader: %w", err)
+		}
+		cut := len(buf)
+		if !last {
+			cut = chunkCut(buf, overlap)
+		}
+
+		c.ExtractInto(string(buf), chunk)
+		for _, m := range chunk.Ordered() {
+			key := sessionKey(m)
+			if m.Start >= cut || seen[key] {
+				continue
+			}
+			seen[key] = true
+			m.Start += base
+			m.End += base
+			if m.Line > 0 {
+				m.Line += lines
+			}
+			res.add(m)
+		}
+		res.Truncated = res.Truncated || chunk.Truncated
+		for typ, n := range chunk.Suppressed {
+			res.Suppressed[typ] += n
+		}
+		if last {
+			return res, nil
+		}
+		lines += bytes.Count(buf[:cut], []byte{'\n'})
+		base += cut
+		buf = buf[:copy(buf, buf[cut:])]
+	}
+}
+
+// chunking returns the chunk size and overlap of ExtractReader.
+func (c *Contextualizer) chunking() (size, overlap int) {
+	size, overlap = c.ChunkSize, c.ChunkOverlap
+	if size <= 0 {
+		size, overlap = defaultChunkSize, defaultChunkOverlap
+	}
+	return size, min(max(overlap, 0), size/2)
+}
+
+// chunkCut returns where to end the chunk at the start of buf, which holds
+// a chunk and its overlap: after the first line break in the overlap, or
+// the first space, or at the start of the overlap.
+func chunkCut(buf []byte, overlap int) int {
+	start := len(buf) - overlap
+	tail := buf[start:]
+	if i := bytes.IndexByte(tail, '\n'); i >= 0 {
+		return start + i + 1
+	}
+	if i := bytes.IndexAny(tail, " \t\r"); i >= 0 {
+		return start + i + 1
+	}
+	return start
+}
@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestContextualizer_ExtractReader(t *testing.T) {
+	var b strings.Builder
+	for i := range 200 {
+		b.WriteString("log line with filler text ")
+		if i%7 == 0 {
+			b.WriteString("callback to 203.0.113.")
+			b.WriteString(strings.Repeat("9", 1+i%3))
+			b.WriteString(" and beacon-long-hostname.example.com\n")
+		} else {
+			b.WriteString("nothing to see here at all\n")
+		}
+	}
+	text := b.String()
+
+	c := NewContextualizer(WithLineNumbers())
+	want := c.ExtractOrdered(text)
+	if len(want) == 0 {
+		t.Fatal("no matches in the whole text")
+	}
+	for _, size := range []int{64, 100, 257, 1 << 20} {
+		chunked := NewContextualizer(WithLineNumbers(), WithChunkSize(size, 48))
+		r, err := chunked.ExtractReader(iotest.HalfReader(strings.NewReader(text)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := r.Ordered()
+		if len(got) != len(want) {
+			t.Errorf("size %d: %d matches, want %d: %+v", size, len(got), len(want), got)
+			continue
+		}
+		for i := range want {
+			g, w := got[i], want[i]
+			if g.Value != w.Value || g.Start != w.Start || g.End != w.End || g.Line != w.Line || g.Column != w.Column {
+				t.Errorf("size %d: match %d = %+v, want %+v", size, i, g, w)
+			}
+			if g.Parent == "" && text[g.Start:g.End] != g.Value {
+				t.Errorf("size %d: text[%d:%d] = %q, want %q", size, g.Start, g.End, text[g.Start:g.End], g.Value)
+			}
+		}
+	}
+}
+
+func TestContextualizer_ExtractFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "huge.log")
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", 90)+" evil.example\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewContextualizer(WithChunkSize(80, 32)).ExtractFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := r.Matches["domain"]; len(d) != 1 || d[0].Value != "evil.example" || d[0].Start != 91 {
+		t.Errorf("domain = %+v", d)
+	}
+
+	if _, err := NewContextualizer().ExtractFile(filepath.Join(t.TempDir(), "missing")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ExtractFile(missing) error = %v", err)
+	}
+	boom := errors.New("boom")
+	if _, err := NewContextualizer().ExtractReader(iotest.ErrReader(boom)); !errors.Is(err, boom) {
+		t.Errorf("ExtractReader error = %v, want boom", err)
+	}
+}
+
+func TestChunkCut(t *testing.T) {
+	tests := []struct {
+		buf  string
+		want int
+	}{
+		{"aaaa bb\ncc dd", 8},
+		{"aaaaaaab cdddd", 9},
+		{"aaaaaaabbbbbb", 7},
+	}
+	for _, tt := range tests {
+		if got := chunkCut([]byte(tt.buf), 6); got != tt.want {
+			t.Errorf("chunkCut(%q) = %d, want %d", tt.buf, got, tt.want)
+		}
+	}
+}
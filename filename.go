@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+)
+
+// DefaultFileExtensions is the FileExtensions used when none is set:
+// extensions of executables, scripts, archives and documents that the
+// domain expression would otherwise take for a TLD. Extensions that are
+// also widely used country codes, such as "pl" or "md", are left out.
+var DefaultFileExtensions = []string{
+	"7z", "apk", "asp", "aspx", "bak", "bat", "bin", "bz2", "cab", "cfg",
+	"class", "cmd", "conf", "csv", "dat", "deb", "dll", "dmg", "doc", "docm",
+	"docx", "elf", "exe", "gif", "gz", "hta", "htm", "html", "img", "ini",
+	"iso", "jar", "jpeg", "jpg", "js", "jse", "json", "jsp", "lnk", "log",
+	"mov", "mp3", "mp4", "msi", "msp", "pdf", "php", "pkg", "png", "ppt",
+	"pptx", "ps1", "psm1", "py", "rar", "rpm", "rtf", "scr", "sh", "svg",
+	"sys", "tar", "tgz", "tmp", "txt", "vbe", "vbs", "wsf", "xls", "xlsm",
+	"xlsx", "xml", "xz", "yaml", "yml", "zip",
+}
+
+// fileLike reports whether the domain candidate at text[start:end] is more
+// likely a filename: its extension is one of FileExtensions and nothing
+// around it says otherwise. A scheme or '@' before it, or a path or port
+// after it, make it a host; a slash before it makes it a path component.
+func (c *Contextualizer) fileLike(text string, start, end int) bool {
+	val := text[start:end]
+	ext := strings.ToLower(val[strings.LastIndexByte(val, '.')+1:])
+	exts := c.FileExtensions
+	if exts == nil {
+		exts = DefaultFileExtensions
+	}
+	if !slices.Contains(exts, ext) {
+		return false
+	}
+	before, after := text[:start], text[end:]
+	switch {
+	case strings.HasSuffix(before, "://"), strings.HasSuffix(before, "@"):
+		return false
+	case strings.HasSuffix(before, "/"), strings.HasSuffix(before, `\`):
+		return true
+	case strings.HasPrefix(after, "/"), strings.HasPrefix(after, ":"):
+		return false
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContextualizer_FileLikeDomains(t *testing.T) {
+	text := "dropped update.zip and ran install.sh from /tmp/stage.zip, " +
+		"then fetched http://payload.zip/a and reports.mov:8080, wrote a@mail.zip, visited evil.com"
+
+	got := NewContextualizer().ExtractAll(text)
+	var domains []string
+	for _, m := range got["domain"] {
+		domains = append(domains, m.Value)
+	}
+	want := []string{"reports.mov", "evil.com"}
+	if !reflect.DeepEqual(domains, want) {
+		t.Errorf("domains = %v, want %v", domains, want)
+	}
+	if len(got["email"]) != 1 || len(got["url"]) != 1 {
+		t.Errorf("email = %v, url = %v", got["email"], got["url"])
+	}
+
+	got = NewContextualizer(WithFileExtensions(".sh")).ExtractAll("update.zip and install.sh")
+	if d := got["domain"]; len(d) != 1 || d[0].Value != "update.zip" {
+		t.Errorf("custom extensions: domain = %v", d)
+	}
+
+	got = NewContextualizer().ExtractAll("update.zip")
+	if got["domain"] != nil || len(got["filename"]) != 1 {
+		t.Errorf("standalone: %v", got)
+	}
+}
+
+func TestContextualizer_FilenamesInText(t *testing.T) {
+	c := NewContextualizer(WithIgnoredDomains("corp.example"))
+	got := c.ExtractAll("The dropper wrote invoice_2024.pdf.exe and payload.tar.gz next to notes.txt, " +
+		"beaconing to evil.com and corp.example; see v1.2 for details.")
+
+	var names []string
+	for _, m := range got["filename"] {
+		names = append(names, m.Value)
+	}
+	want := []string{"invoice_2024.pdf.exe", "payload.tar.gz", "notes.txt"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("filenames = %v, want %v", names, want)
+	}
+	if d := got["domain"]; len(d) != 1 || d[0].Value != "evil.com" {
+		t.Errorf("domain = %v", d)
+	}
+}
+
+func TestContextualizer_FilePaths(t *testing.T) {
+	c := NewContextualizer()
+	text := `ran /usr/local/bin/evil and ~/.bashrc, then ./stage2/run.sh from evil/loader.bin; ` +
+		`persisted in "/Users/John Smith/Library/LaunchAgents/com.evil.plist". ` +
+		`Upgraded 1.2/3.4 on 12/25/2024, see /tmp.`
+
+	var paths []string
+	for _, m := range c.ExtractAll(text)["filepath"] {
+		paths = append(paths, m.Value)
+	}
+	want := []string{
+		"/usr/local/bin/evil",
+		"~/.bashrc",
+		"./stage2/run.sh",
+		"evil/loader.bin",
+		"/Users/John Smith/Library/LaunchAgents/com.evil.plist",
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("filepaths = %q, want %q", paths, want)
+	}
+}
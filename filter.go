@@ -0,0 +1,34 @@
+package parser
+
+// Filter inspects a match about to be returned and returns it, possibly
+// changed, along with whether to keep it. Filters let deployments apply
+// their own rules, such as dropping internal ticket URLs or renaming
+// kinds, without changing the expressions.
+type Filter func(m Match) (Match, bool)
+
+// filter marks, classifies and tags m and runs it through c.Filters in order,
+// stopping at the first one that drops it.
+func (c *Contextualizer) filter(m Match) (Match, bool) {
+	c.mu.RLock()
+	m.TLP = c.TLP
+	m = c.classify(m)
+	c.mu.RUnlock()
+	m = c.tag(m)
+	for _, f := range c.Filters {
+		var keep bool
+		if m, keep = f(m); !keep {
+			return m, false
+		}
+	}
+	return m, true
+}
+
+// appendFiltered appends the matches that pass c.Filters to dst.
+func (c *Contextualizer) appendFiltered(dst []Match, matches ...Match) []Match {
+	for _, m := range matches {
+		if m, keep := c.filter(m); keep {
+			dst = append(dst, m)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,35 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContextualizer_Filters(t *testing.T) {
+	dropTickets := func(m Match) (Match, bool) {
+		return m, !(m.Type == "url" && strings.Contains(m.Value, "tickets.corp.example"))
+	}
+	renameMD5 := func(m Match) (Match, bool) {
+		if m.Type == "md5" {
+			m.Type = "file_hash"
+		}
+		return m, true
+	}
+	text := "see https://tickets.corp.example/T-1 and http://evil.example/x, hash d41d8cd98f00b204e9800998ecf8427e"
+
+	for _, workers := range []int{1, 4} {
+		c := NewContextualizer(WithFilters(dropTickets, renameMD5), WithWorkers(workers))
+		got := c.ExtractAll(text)
+		if u := got["url"]; len(u) != 1 || u[0].Value != "http://evil.example/x" {
+			t.Errorf("workers %d: url = %+v", workers, u)
+		}
+		if got["md5"] != nil || len(got["file_hash"]) != 1 {
+			t.Errorf("workers %d: md5 = %+v, file_hash = %+v", workers, got["md5"], got["file_hash"])
+		}
+	}
+
+	c := NewContextualizer(WithFilters(dropTickets))
+	if m := c.GetMatches(text, "url", c.Expressions["url"]); len(m) != 1 {
+		t.Errorf("GetMatches = %+v", m)
+	}
+}
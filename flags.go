@@ -0,0 +1,24 @@
+package parser
+
+// ScanFlag adjusts a single extraction, see ExtractAllFlags.
+type ScanFlag uint8
+
+const (
+	// NoDedup reports every occurrence of a value, each with its own
+	// offsets, instead of only the first.
+	NoDedup ScanFlag = 1 << iota
+	// NoIgnore skips the ignore lists in PrivateChecks, so allowlisted
+	// indicators are reported too. The per-kind sanity checks, such as
+	// rejecting out-of-range IPv4 octets, still apply.
+	NoIgnore
+)
+
+// noChecks are the empty ignore lists used under NoIgnore. They must not
+// be modified.
+var noChecks = &PrivateChecks{}
+
+// ExtractAllFlags is ExtractAll adjusted by flags, for auditing tools that
+// need to see every raw occurrence, including allowlisted ones.
+func (c *Contextualizer) ExtractAllFlags(text string, flags ScanFlag) map[string][]Match {
+	return c.extract(text, c.expressions(), c.Entropy, flags)
+}
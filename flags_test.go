@@ -0,0 +1,32 @@
+package parser
+
+import "testing"
+
+func TestContextualizer_ExtractAllFlags(t *testing.T) {
+	c := NewContextualizer(WithIgnoredDomains("corp.example"), WithIgnorePrivateIPs(true))
+	text := "evil.com then evil.com, host corp.example from 10.0.0.1 and 10.2.300.4"
+
+	got := c.ExtractAll(text)
+	if len(got["domain"]) != 1 || got["ipv4"] != nil {
+		t.Fatalf("ExtractAll() = %v", got)
+	}
+
+	got = c.ExtractAllFlags(text, NoDedup)
+	if d := got["domain"]; len(d) != 2 || d[0].Start == d[1].Start {
+		t.Errorf("NoDedup domain = %v", d)
+	}
+
+	got = c.ExtractAllFlags(text, NoIgnore)
+	if len(got["domain"]) != 2 || len(got["ipv4"]) != 1 || got["ipv4"][0].Value != "10.0.0.1" {
+		t.Errorf("NoIgnore = %v", got)
+	}
+
+	got = c.ExtractAllFlags(text, NoDedup|NoIgnore)
+	if len(got["domain"]) != 3 {
+		t.Errorf("NoDedup|NoIgnore domain = %v", got["domain"])
+	}
+	c.Workers = 4
+	if par := c.ExtractAllFlags(text, NoDedup|NoIgnore); len(par["domain"]) != 3 {
+		t.Errorf("parallel NoDedup|NoIgnore domain = %v", par["domain"])
+	}
+}
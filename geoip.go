@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"context"
+	"net/netip"
+	"strconv"
+)
+
+// GeoRecord is what a GeoLookup knows about an address. Zero fields are
+// unknown.
+type GeoRecord struct {
+	// Country is the ISO 3166-1 alpha-2 code of the country.
+	Country string
+	// ASN and ASOrg identify the autonomous system announcing the address.
+	ASN   uint
+	ASOrg string
+}
+
+// GeoLookup looks up addresses in a GeoIP database, such as a MaxMind
+// GeoLite2 reader wrapped by the caller. An address that is not in the
+// database is a zero GeoRecord, not an error.
+type GeoLookup interface {
+	Lookup(addr netip.Addr) (GeoRecord, error)
+}
+
+// GeoIPEnricher is an Enricher that annotates ipv4, ipv6 and ipport
+// matches with "geoip.country", "geoip.asn" and "geoip.as_org" metadata.
+type GeoIPEnricher struct {
+	DB GeoLookup
+}
+
+// NewGeoIPEnricher returns a GeoIPEnricher using db.
+func NewGeoIPEnricher(db GeoLookup) *GeoIPEnricher {
+	return &GeoIPEnricher{DB: db}
+}
+
+// Name implements Enricher.
+func (*GeoIPEnricher) Name() string { return "geoip" }
+
+// Enrich implements Enricher.
+func (g *GeoIPEnricher) Enrich(_ context.Context, m Match) (Enrichment, error) {
+	var addr netip.Addr
+	switch m.Type {
+	case "ipv4", "ipv6":
+		a, err := netip.ParseAddr(Refang(m.Value))
+		if err != nil {
+			return Enrichment{}, nil
+		}
+		addr = a
+	case "ipport":
+		ap, err := netip.ParseAddrPort(Refang(m.Value))
+		if err != nil {
+			return Enrichment{}, nil
+		}
+		addr = ap.Addr()
+	default:
+		return Enrichment{}, nil
+	}
+
+	rec, err := g.DB.Lookup(addr.WithZone(""))
+	if err != nil {
+		return Enrichment{}, err
+	}
+	meta := make(map[string]string, 3)
+	if rec.Country != "" {
+		meta["country"] = rec.Country
+	}
+	if rec.ASN != 0 {
+		meta["asn"] = strconv.FormatUint(uint64(rec.ASN), 10)
+	}
+	if rec.ASOrg != "" {
+		meta["as_org"] = rec.ASOrg
+	}
+	return Enrichment{Metadata: meta}, nil
+}
@@ -0,0 +1,36 @@
+package parser
+
+import (
+	"context"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+type fakeGeoDB map[netip.Addr]GeoRecord
+
+func (f fakeGeoDB) Lookup(addr netip.Addr) (GeoRecord, error) {
+	return f[addr], nil
+}
+
+func TestGeoIPEnricher(t *testing.T) {
+	db := fakeGeoDB{
+		netip.MustParseAddr("192.0.2.1"): {Country: "NL", ASN: 64500, ASOrg: "Example Hosting"},
+	}
+	c := NewContextualizer(WithEnricher(NewGeoIPEnricher(db), 0))
+	r := NewResult()
+	c.ExtractInto("seen 192.0.2.1:8080 and 198.51.100.7", r)
+
+	if err := c.Enrich(context.Background(), r); err != nil {
+		t.Fatalf("Enrich() error = %v", err)
+	}
+	want := map[string]string{"geoip.country": "NL", "geoip.asn": "64500", "geoip.as_org": "Example Hosting"}
+	if got := r.Matches["ipport"][0].Metadata; !reflect.DeepEqual(got, want) {
+		t.Errorf("ipport Metadata = %v, want %v", got, want)
+	}
+	for _, m := range r.Matches["ipv4"] {
+		if m.Value == "198.51.100.7" && m.Metadata != nil {
+			t.Errorf("unknown address Metadata = %v", m.Metadata)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package parser
+
+import "strings"
+
+// gitLeadKeywords mark a 40-hex value as a commit when they appear shortly
+// before it on the same line, as in "commit <sha>" from git log or
+// "(cherry picked from commit <sha>)".
+var gitLeadKeywords = []string{"commit", "merge", "revert", "parent", "cherry"}
+
+// gitLeadSuffixes mark a value as a commit when they immediately precede
+// it, as in repository URLs ("/commit/<sha>") and pinned module versions
+// ("repo@<sha>").
+var gitLeadSuffixes = []string{"/commit/", "/commits/", "/tree/", "/blob/", "/-/commit/", "@"}
+
+// gitCommitContext reports whether the sha1 candidate at text[start:end]
+// reads as a git commit: a commit keyword or repository URL path before
+// it, or a ref such as "refs/heads/main" or "(HEAD -> main)" after it,
+// as printed by git ls-remote and git log --decorate.
+func gitCommitContext(text string, start, end int) bool {
+	lead := leadText(text, start)
+	for _, suffix := range gitLeadSuffixes {
+		if strings.HasSuffix(lead, suffix) {
+			return true
+		}
+	}
+	for _, kw := range gitLeadKeywords {
+		if strings.Contains(lead, kw) {
+			return true
+		}
+	}
+
+	trail := text[end:min(len(text), end+contextWindow)]
+	if nl := strings.IndexByte(trail, '\n'); nl != -1 {
+		trail = trail[:nl]
+	}
+	trail = strings.TrimLeft(trail, " \t")
+	return strings.HasPrefix(trail, "refs/") || strings.HasPrefix(trail, "(HEAD") || strings.HasPrefix(trail, "HEAD")
+}
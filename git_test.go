@@ -0,0 +1,23 @@
+package parser
+
+import "testing"
+
+func TestContextualizer_GitCommits(t *testing.T) {
+	text := "commit 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n" +
+		"see github.com/org/repo/commit/da39a3ee5e6b4b0d3255bfef95601890afd80709\n" +
+		"0c1f1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b\trefs/heads/main\n" +
+		"dropper sha1 356a192b7913b04c54574d18c28d46e6395428ab"
+
+	got := NewContextualizer().ExtractAll(text)
+	if len(got["sha1"]) != 4 || got["git_commit"] != nil {
+		t.Errorf("default: sha1 = %d, git_commit = %v", len(got["sha1"]), got["git_commit"])
+	}
+
+	got = NewContextualizer(WithGitCommits()).ExtractAll(text)
+	if len(got["git_commit"]) != 3 {
+		t.Errorf("git_commit = %v", got["git_commit"])
+	}
+	if s := got["sha1"]; len(s) != 1 || s[0].Value != "356a192b7913b04c54574d18c28d46e6395428ab" {
+		t.Errorf("sha1 = %v", s)
+	}
+}
@@ -2,4 +2,12 @@ module github.com/rexlx/parser
 
 go 1.24.1
 
-require golang.org/x/net v0.48.0
+require (
+	go.etcd.io/bbolt v1.4.3
+	golang.org/x/net v0.48.0
+)
+
+require (
+	golang.org/x/sys v0.39.0 // indirect
+	golang.org/x/text v0.32.0 // indirect
+)
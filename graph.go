@@ -0,0 +1,138 @@
+package parser
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Node is an indicator in the relationship graph of a Result.
+type Node struct {
+	Type  string
+	Value string
+}
+
+// Edge links an indicator to one it contains, such as a URL to its
+// hostname. Relation names the part, for example "host" or "base_domain".
+type Edge struct {
+	From     Node
+	To       Node
+	Relation string
+}
+
+// Edges returns the relationships between the matches of r, in document
+// order and without duplicates:
+//
+//   - url to its hostname, and unc to its host ("host")
+//   - email to its domain ("domain")
+//   - domain to its base_domain ("base_domain")
+//   - ipport to its address and port ("ip", "port")
+//
+// The target of an edge need not be a match itself; the domain of a URL,
+// for instance, is shadowed by the URL during extraction.
+func (r *Result) Edges() []Edge {
+	ordered := r.Sorted(ByPosition)
+	// Derived matches share the offsets of their parent.
+	parents := make(map[parentKey]Node)
+	for _, m := range ordered {
+		if m.Parent == "" {
+			parents[parentKey{m.Start, m.End, m.Value}] = Node{m.Type, m.Value}
+		}
+	}
+
+	var edges []Edge
+	seen := make(map[Edge]bool)
+	add := func(e Edge) {
+		if e.From.Value != "" && e.To.Value != "" && !seen[e] {
+			seen[e] = true
+			edges = append(edges, e)
+		}
+	}
+	for _, m := range ordered {
+		if m.Parent != "" {
+			if from, ok := parents[parentKey{m.Start, m.End, m.Parent}]; ok {
+				add(Edge{From: from, To: Node{m.Type, m.Value}, Relation: relation(from.Type, m.Type)})
+			}
+			continue
+		}
+		// Matches written with DefangOutput are refanged to find the host,
+		// which is then defanged again to join the node of its own match.
+		val := Refang(m.Value)
+		output := func(host string) string {
+			if val != m.Value {
+				return Defang(host)
+			}
+			return host
+		}
+		switch m.Type {
+		case "url":
+			if host := urlHost(val); host != "" {
+				add(Edge{From: Node{m.Type, m.Value}, To: Node{hostKind(host), output(host)}, Relation: "host"})
+			}
+		case "email":
+			if _, domain, ok := strings.Cut(val, "@"); ok {
+				add(Edge{From: Node{m.Type, m.Value}, To: Node{"domain", output(strings.ToLower(domain))}, Relation: "domain"})
+			}
+		}
+	}
+	return edges
+}
+
+type parentKey struct {
+	start, end int
+	value      string
+}
+
+// relation names the edge from a match of type from to a match of type to
+// derived from it.
+func relation(from, to string) string {
+	switch {
+	case from == "url", from == "unc":
+		return "host"
+	case to == "ipv4", to == "ipv6":
+		return "ip"
+	}
+	return to
+}
+
+func urlHost(val string) string {
+	u, err := url.Parse(Refang(val))
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// ToDOT renders the relationship graph of r in Graphviz DOT format, one
+// node per indicator labelled with its value and type. Matches without
+// relationships are included as lone nodes. A marked Result labels the
+// graph with its TLP.
+func (r *Result) ToDOT() string {
+	var b strings.Builder
+	b.WriteString("digraph indicators {\n")
+	b.WriteString("\tnode [shape=box];\n")
+	if tlp := r.Marking(); tlp != "" {
+		fmt.Fprintf(&b, "\tlabel=%s;\n\tlabelloc=t;\n", strconv.Quote(tlp.String()))
+	}
+
+	ids := make(map[Node]string)
+	node := func(n Node) string {
+		if id, ok := ids[n]; ok {
+			return id
+		}
+		id := "n" + strconv.Itoa(len(ids))
+		ids[n] = id
+		fmt.Fprintf(&b, "\t%s [label=%s];\n", id, strconv.Quote(n.Value+"\n"+n.Type))
+		return id
+	}
+	for _, m := range r.Sorted(ByPosition) {
+		node(Node{m.Type, m.Value})
+	}
+	for _, e := range r.Edges() {
+		from, to := node(e.From), node(e.To)
+		fmt.Fprintf(&b, "\t%s -> %s [label=%s];\n", from, to, strconv.Quote(e.Relation))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
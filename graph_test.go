@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResult_Edges(t *testing.T) {
+	c := NewContextualizer()
+	r := NewResult()
+
+	c.ExtractInto("go to http://Evil.com/x, mail bob@evil.org, host a.b.test.org, connect 8.8.8.8:443", r)
+	want := []Edge{
+		{Node{"url", "http://Evil.com/x"}, Node{"domain", "evil.com"}, "host"},
+		{Node{"email", "bob@evil.org"}, Node{"domain", "evil.org"}, "domain"},
+		{Node{"domain", "a.b.test.org"}, Node{"base_domain", "test.org"}, "base_domain"},
+		{Node{"ipport", "8.8.8.8:443"}, Node{"ipv4", "8.8.8.8"}, "ip"},
+		{Node{"ipport", "8.8.8.8:443"}, Node{"port", "443"}, "port"},
+	}
+	if got := r.Edges(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Edges() =\n%v\nwant\n%v", got, want)
+	}
+
+	// Defanged output still joins the domain match of the same host.
+	c = NewContextualizer(WithDefangOutput())
+	c.ExtractInto("mail bob@example.com about https://example.com/x and example.com", r)
+	want = []Edge{
+		{Node{"email", "bob[@]example[.]com"}, Node{"domain", "example[.]com"}, "domain"},
+		{Node{"url", "hxxps://example[.]com/x"}, Node{"domain", "example[.]com"}, "host"},
+	}
+	if got := r.Edges(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DefangOutput Edges() =\n%v\nwant\n%v", got, want)
+	}
+	if d := r.Matches["domain"]; len(d) != 1 || d[0].Value != "example[.]com" {
+		t.Errorf("domain = %v", d)
+	}
+}
+
+func TestResult_ToDOT(t *testing.T) {
+	c := NewContextualizer()
+	r := NewResult()
+
+	c.ExtractInto("connect 8.8.8.8:443", r)
+	want := `digraph indicators {
+	node [shape=box];
+	n0 [label="8.8.8.8:443\nipport"];
+	n1 [label="8.8.8.8\nipv4"];
+	n2 [label="443\nport"];
+	n0 -> n1 [label="ip"];
+	n0 -> n2 [label="port"];
+}
+`
+	if got := r.ToDOT(); got != want {
+		t.Errorf("ToDOT() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,58 @@
+package parser
+
+import "strings"
+
+// hashIDKeywords introduce identifiers that are hex but not digests.
+var hashIDKeywords = []string{"uuid", "guid", "trace", "span", "session", "request", "correlation"}
+
+// hashNoise returns how much less likely the hash candidate at
+// text[start:end] is a real digest than its kind's base confidence says:
+// it sits inside a longer hex run ("<hex>-<hex>") or a bigger token such as
+// a JWT or base64 blob, has the version and variant nibbles of an
+// undashed UUID, mixes upper and lower case, which hashing tools never do,
+// or follows an identifier keyword such as "uuid" or "trace".
+// All-numeric values are already demoted by confidence.
+func hashNoise(text string, start, end int) float64 {
+	val := text[start:end]
+	var noise float64
+
+	tokStart, tokEnd := start, end
+	for tokStart > 0 && isTokenByte(text[tokStart-1]) {
+		tokStart--
+	}
+	for tokEnd < len(text) && isTokenByte(text[tokEnd]) {
+		tokEnd++
+	}
+	if token := text[tokStart:tokEnd]; len(token) > len(val) {
+		switch {
+		case strings.HasPrefix(token, "eyJ"):
+			noise += 0.5
+		case strings.Trim(token, "0123456789abcdefABCDEF-_.:") == "":
+			noise += 0.4
+		default:
+			noise += 0.3
+		}
+	}
+
+	if len(val) == 32 && strings.IndexByte("12345678", val[12]) >= 0 && strings.IndexByte("89abAB", val[16]) >= 0 {
+		noise += 0.2
+	}
+	if strings.ToLower(val) != val && strings.ToUpper(val) != val {
+		noise += 0.2
+	}
+
+	lead := strings.ToLower(text[max(0, start-contextWindow):start])
+	for _, kw := range hashIDKeywords {
+		if strings.Contains(lead, kw) {
+			noise += 0.3
+			break
+		}
+	}
+	return noise
+}
+
+// isTokenByte reports whether b continues an identifier, base64 or JWT
+// token around a hex value.
+func isTokenByte(b byte) bool {
+	return b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || strings.IndexByte("-_.+/=:", b) >= 0
+}
@@ -0,0 +1,24 @@
+package parser
+
+import "testing"
+
+func TestContextualizer_HashHeuristics(t *testing.T) {
+	text := "md5 d41d8cd98f00b204e9800998ecf8427e\n" +
+		"uuid 6ba7b8109dad11d180b400c04fd430c8\n" +
+		"blob d41d8cd98f00b204e9800998ecf84270-00ff\n" +
+		"mixed D41d8cd98f00B204e9800998ecf8427f\n"
+
+	plain := NewContextualizer(WithConfidence()).ExtractAll(text)["md5"]
+	tuned := NewContextualizer(WithHashHeuristics()).ExtractAll(text)["md5"]
+	if len(plain) != 4 || len(tuned) != 4 {
+		t.Fatalf("md5 = %v, %v", plain, tuned)
+	}
+	if plain[0].Confidence != tuned[0].Confidence {
+		t.Errorf("digest demoted: %v -> %v", plain[0].Confidence, tuned[0].Confidence)
+	}
+	for i := 1; i < 4; i++ {
+		if tuned[i].Confidence >= plain[i].Confidence {
+			t.Errorf("%s not demoted: %v -> %v", tuned[i].Value, plain[i].Confidence, tuned[i].Confidence)
+		}
+	}
+}
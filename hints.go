@@ -0,0 +1,35 @@
+package parser
+
+import "strings"
+
+// KeywordHint tunes matches of a kind that follow one of its keywords on
+// the same line, such as "C2:" before a domain or "sha256sum" before a
+// hash in a sandbox report.
+type KeywordHint struct {
+	// Keywords are matched case-insensitively in the text just before the
+	// match.
+	Keywords []string `json:"keywords"`
+	// Boost is added to Match.Confidence, when scoring, and may be
+	// negative.
+	Boost float64 `json:"boost,omitempty"`
+	// Type, when set, re-types the match, e.g. an md5 after "imphash".
+	Type string `json:"type,omitempty"`
+}
+
+// keywordHint returns the first hint registered for kind whose keyword
+// precedes text[start].
+func (c *Contextualizer) keywordHint(kind, text string, start int) (KeywordHint, bool) {
+	hints := c.KeywordHints[kind]
+	if len(hints) == 0 {
+		return KeywordHint{}, false
+	}
+	lead := leadText(text, start)
+	for _, hint := range hints {
+		for _, kw := range hint.Keywords {
+			if strings.Contains(lead, strings.ToLower(kw)) {
+				return hint, true
+			}
+		}
+	}
+	return KeywordHint{}, false
+}
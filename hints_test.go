@@ -0,0 +1,35 @@
+package parser
+
+import "testing"
+
+func TestContextualizer_KeywordHints(t *testing.T) {
+	c := NewContextualizer(
+		WithConfidence(),
+		WithKeywordHints("md5", KeywordHint{Keywords: []string{"imphash"}, Type: "imphash"}),
+		WithKeywordHints("domain", KeywordHint{Keywords: []string{"Sinkholed"}, Boost: -0.4}),
+	)
+	text := "Imphash: f34d5f2d4577ed6d9ceec516c1f5a744\n" +
+		"sinkholed by evil.com\n" +
+		"the payload was downloaded from help.com"
+
+	got := c.ExtractAll(text)
+	if m := got["imphash"]; len(m) != 1 || m[0].Value != "f34d5f2d4577ed6d9ceec516c1f5a744" {
+		t.Errorf("imphash = %v", m)
+	}
+	if got["md5"] != nil {
+		t.Errorf("md5 = %v", got["md5"])
+	}
+	d := got["domain"]
+	if len(d) != 2 || d[0].Value != "evil.com" || d[0].Confidence >= d[1].Confidence {
+		t.Errorf("domain = %v", d)
+	}
+
+	cfg := c.Config()
+	restored := NewContextualizer()
+	if err := restored.ApplyConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if restored.ExtractAll(text)["imphash"] == nil {
+		t.Error("keyword hints lost in Config round trip")
+	}
+}
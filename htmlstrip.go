@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// linkAttrs are the attributes whose values stripHTML keeps, since they
+// hold the links a page points to.
+var linkAttrs = map[string]bool{
+	"href":   true,
+	"src":    true,
+	"action": true,
+}
+
+// stripHTML reduces an HTML document to its visible text and the values of
+// its link attributes, dropping tags, comments, scripts and styles. Every
+// tag becomes a space so that text on either side doesn't run together.
+// It returns the result along with the offset in text of each of its
+// bytes, in the form refang uses. Character references are left as
+// written; DecodeEscapes resolves them.
+func stripHTML(text string) (string, []int) {
+	var b strings.Builder
+	b.Grow(len(text))
+	pos := make([]int, 0, len(text)+1)
+	write := func(s string, at func(i int) int) {
+		b.WriteString(s)
+		for i := range len(s) {
+			pos = append(pos, at(i))
+		}
+	}
+
+	z := html.NewTokenizer(strings.NewReader(text))
+	var offset int
+	var hidden atom.Atom // the script or style element being skipped
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			// Malformed markup is tokenized leniently, so this is EOF.
+			break
+		}
+		raw := string(z.Raw())
+		start := offset
+		offset += len(raw)
+
+		switch tt {
+		case html.TextToken:
+			if hidden == 0 {
+				write(raw, func(i int) int { return start + i })
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			if a := atom.Lookup(name); tt == html.StartTagToken && (a == atom.Script || a == atom.Style) {
+				hidden = a
+			}
+			write(" ", func(int) int { return start })
+			// Offsets must not go backwards, so values are looked up past
+			// the previous one.
+			from := 0
+			for hasAttr {
+				var key, val []byte
+				key, val, hasAttr = z.TagAttr()
+				if !linkAttrs[string(key)] || len(val) == 0 {
+					continue
+				}
+				// The tokenizer unescapes values, so the value is written
+				// as it appears in the tag, like text, when it can be found.
+				if vs, ve, ok := attrValue(raw[from:], string(key)); ok {
+					base := start + from + vs
+					write(raw[from+vs:from+ve], func(i int) int { return base + i })
+					from += ve
+				} else {
+					write(string(val), func(int) int { return start + from })
+				}
+				write(" ", func(int) int { return start + from })
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			if atom.Lookup(name) == hidden {
+				hidden = 0
+			}
+			write(" ", func(int) int { return start })
+		}
+	}
+	pos = append(pos, len(text))
+	return b.String(), pos
+}
+
+// attrValue locates the value of the first attribute named key in the raw
+// tag, without its quotes.
+func attrValue(raw, key string) (start, end int, ok bool) {
+	lower := strings.ToLower(raw)
+	for i := 0; ; {
+		idx := strings.Index(lower[i:], key)
+		if idx < 0 {
+			return 0, 0, false
+		}
+		i += idx
+		j := i + len(key)
+		if i == 0 || !isHTMLSpace(raw[i-1]) {
+			i = j
+			continue
+		}
+		for j < len(raw) && isHTMLSpace(raw[j]) {
+			j++
+		}
+		if j == len(raw) || raw[j] != '=' {
+			i = j
+			continue
+		}
+		j++
+		for j < len(raw) && isHTMLSpace(raw[j]) {
+			j++
+		}
+		if j < len(raw) && (raw[j] == '"' || raw[j] == '\'') {
+			if end := strings.IndexByte(raw[j+1:], raw[j]); end >= 0 {
+				return j + 1, j + 1 + end, true
+			}
+			return 0, 0, false
+		}
+		end := j
+		for end < len(raw) && !isHTMLSpace(raw[end]) && raw[end] != '>' {
+			end++
+		}
+		return j, end, end > j
+	}
+}
+
+func isHTMLSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f'
+}
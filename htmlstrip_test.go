@@ -0,0 +1,36 @@
+package parser
+
+import "testing"
+
+func TestStripHTML(t *testing.T) {
+	in := `<html><head><style>a{background:url(http://style.example/x.png)}</style>` +
+		`<script>var u="http://script.example/";</script></head>` +
+		`<body><p>Hello<b>there</b></p><a href="https://link.example/a?b=1&amp;c=2">click</a><img src=http://img.example/p.gif><!-- http://comment.example --></body></html>`
+
+	got, pos := stripHTML(in)
+	want := "         Hello there   https://link.example/a?b=1&amp;c=2 click  http://img.example/p.gif   "
+	if got != want {
+		t.Errorf("stripHTML() = %q, want %q", got, want)
+	}
+	if len(pos) != len(got)+1 {
+		t.Fatalf("len(pos) = %d, want %d", len(pos), len(got)+1)
+	}
+	for i := 1; i < len(pos); i++ {
+		if pos[i] < pos[i-1] {
+			t.Fatalf("pos[%d] = %d goes back from %d", i, pos[i], pos[i-1])
+		}
+	}
+}
+
+func TestContextualizer_StripHTML(t *testing.T) {
+	text := `<script>fetch("http://tracker.example/t")</script><p>Visit <a href="https://phish.example/login">our site</a> or evil.example</p>`
+
+	c := NewContextualizer(WithHTMLStripping())
+	got := c.ExtractAll(text)
+	if u := got["url"]; len(u) != 1 || u[0].Value != "https://phish.example/login" || text[u[0].Start:u[0].End] != u[0].Value {
+		t.Errorf("url = %+v", u)
+	}
+	if d := got["domain"]; len(d) != 1 || d[0].Value != "evil.example" || text[d[0].Start:d[0].End] != "evil.example" {
+		t.Errorf("domain = %+v", d)
+	}
+}
@@ -0,0 +1,328 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"net/netip"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// LoadIgnoredDomains reads newline-separated domains from r and ignores
+// them along with their subdomains. Blank lines and everything after a '#'
+// are skipped. It returns the number of entries read.
+func (c *Contextualizer) LoadIgnoredDomains(r io.Reader) (int, error) {
+	domains, err := readList(r, nil)
+	if err != nil {
+		return 0, err
+	}
+	c.updateChecks(func(p *PrivateChecks) { p.ignoreDomains(domains...) })
+	return len(domains), nil
+}
+
+// LoadIgnoredEmails reads newline-separated email addresses from r and
+// ignores them, like LoadIgnoredDomains.
+func (c *Contextualizer) LoadIgnoredEmails(r io.Reader) (int, error) {
+	emails, err := readList(r, nil)
+	if err != nil {
+		return 0, err
+	}
+	c.updateChecks(func(p *PrivateChecks) { p.ignoreEmails(emails...) })
+	return len(emails), nil
+}
+
+// LoadIgnoredIPs reads newline-separated IPv4 and IPv6 addresses or CIDR
+// ranges from r and ignores them, like LoadIgnoredDomains. An entry that
+// is neither fails the whole load and nothing is ignored.
+func (c *Contextualizer) LoadIgnoredIPs(r io.Reader) (int, error) {
+	entries, err := readList(r, func(entry string) error {
+		_, err := parseIPOrCIDR(entry)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	c.updateChecks(func(p *PrivateChecks) {
+		for _, entry := range entries {
+			if prefix, _ := parseIPOrCIDR(entry); strings.Contains(entry, "/") {
+				p.ignoreCIDRs(prefix)
+			} else {
+				p.ignoreIPs(entry)
+			}
+		}
+	})
+	return len(entries), nil
+}
+
+// parseIPOrCIDR parses an address as a single-address prefix, or a CIDR
+// range.
+func parseIPOrCIDR(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		return netip.ParsePrefix(s)
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// LoadIgnoredHashes reads newline-separated md5, sha1, sha256 or sha512
+// digests from r and ignores them, like LoadIgnoredDomains. An entry that
+// is not a hex digest of one of those lengths fails the whole load.
+func (c *Contextualizer) LoadIgnoredHashes(r io.Reader) (int, error) {
+	hashes, err := readList(r, func(entry string) error {
+		if !validHashDigest(entry) {
+			return fmt.Errorf("invalid hash %q", entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	c.updateChecks(func(p *PrivateChecks) { p.ignoreHashes(hashes...) })
+	return len(hashes), nil
+}
+
+// AddIgnorePattern compiles pattern and drops matches of kind whose value
+// it matches, e.g. `\.internal\.corp$` for domain or `/healthz` for url.
+// Like regexp.MatchString the expression is unanchored, and it is tried
+// against both the value as found and its lowercase form.
+func (c *Contextualizer) AddIgnorePattern(kind, pattern string) error {
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("compiling ignore pattern for %q: %w", kind, err)
+	}
+	c.updateChecks(func(p *PrivateChecks) { p.ignorePatterns(kind, regex) })
+	return nil
+}
+
+// readList streams the entries of a newline-separated list, skipping blank
+// lines and comments, and checks each with valid when it is non-nil.
+func readList(r io.Reader, valid func(string) error) ([]string, error) {
+	var entries []string
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		entry, _, _ := strings.Cut(sc.Text(), "#")
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if valid != nil {
+			if err := valid(entry); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading list: %w", err)
+	}
+	return entries, nil
+}
+
+// updateChecks applies fn to a copy of the PrivateChecks and swaps it in,
+// so extractions that are running keep a consistent view. fn is applied to
+// c.added as well, so the entries survive a Reload.
+func (c *Contextualizer) updateChecks(fn func(*PrivateChecks)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	checks := c.Checks.clone()
+	fn(checks)
+	c.Checks = checks
+	if c.added == nil {
+		c.added = (&PrivateChecks{}).clone()
+	}
+	fn(c.added)
+}
+
+// merge adds the lists of q to p.
+func (p *PrivateChecks) merge(q *PrivateChecks) {
+	p.IgnoredDomains = union(p.IgnoredDomains, q.IgnoredDomains)
+	p.IgnoredEmails = union(p.IgnoredEmails, q.IgnoredEmails)
+	p.IgnoredIPs = union(p.IgnoredIPs, q.IgnoredIPs)
+	p.ignoreCIDRs(q.IgnoredCIDRs...)
+	for kind, regexes := range q.IgnoredPatterns {
+		p.ignorePatterns(kind, regexes...)
+	}
+	p.IgnoredHashes = union(p.IgnoredHashes, q.IgnoredHashes)
+	p.TopDomains = union(p.TopDomains, q.TopDomains)
+}
+
+func union[K comparable](dst, src map[K]struct{}) map[K]struct{} {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[K]struct{}, len(src))
+	}
+	maps.Copy(dst, src)
+	return dst
+}
+
+func (p *PrivateChecks) clone() *PrivateChecks {
+	checks := *p
+	checks.IgnoredDomains = maps.Clone(p.IgnoredDomains)
+	checks.IgnoredEmails = maps.Clone(p.IgnoredEmails)
+	checks.IgnoredIPs = maps.Clone(p.IgnoredIPs)
+	checks.IgnoredCIDRs = slices.Clone(p.IgnoredCIDRs)
+	checks.IgnoredPatterns = maps.Clone(p.IgnoredPatterns)
+	checks.IgnoredHashes = maps.Clone(p.IgnoredHashes)
+	checks.TopDomains = maps.Clone(p.TopDomains)
+	if checks.IgnoredDomains == nil {
+		checks.IgnoredDomains = make(map[string]struct{})
+	}
+	if checks.IgnoredEmails == nil {
+		checks.IgnoredEmails = make(map[string]struct{})
+	}
+	return &checks
+}
+
+func (p *PrivateChecks) ignoreIPs(ips ...string) {
+	if p.IgnoredIPs == nil {
+		p.IgnoredIPs = make(map[netip.Addr]struct{}, len(ips))
+	}
+	for _, ip := range ips {
+		if addr, err := netip.ParseAddr(ip); err == nil {
+			p.IgnoredIPs[addr.WithZone("").Unmap()] = struct{}{}
+		}
+	}
+}
+
+// ipListed reports whether addr is in IgnoredIPs or IgnoredCIDRs.
+func (p *PrivateChecks) ipListed(addr netip.Addr) bool {
+	addr = addr.WithZone("").Unmap()
+	if _, ok := p.IgnoredIPs[addr]; ok {
+		return true
+	}
+	for _, prefix := range p.IgnoredCIDRs {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// bogonPrefixes are the reserved ranges dropped by IgnoreBogons: the IANA
+// special-purpose registries minus the globally reachable entries, plus
+// multicast and the old site-local range.
+var bogonPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("127.0.0.0/8"),
+	netip.MustParsePrefix("169.254.0.0/16"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("224.0.0.0/4"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("::/128"),
+	netip.MustParsePrefix("::1/128"),
+	netip.MustParsePrefix("100::/64"),
+	netip.MustParsePrefix("2001:2::/48"),
+	netip.MustParsePrefix("2001:10::/28"),
+	netip.MustParsePrefix("2001:db8::/32"),
+	netip.MustParsePrefix("3fff::/20"),
+	netip.MustParsePrefix("fc00::/7"),
+	netip.MustParsePrefix("fe80::/10"),
+	netip.MustParsePrefix("fec0::/10"),
+	netip.MustParsePrefix("ff00::/8"),
+}
+
+func isBogon(addr netip.Addr) bool {
+	addr = addr.WithZone("").Unmap()
+	for _, prefix := range bogonPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// addrIgnored reports whether addr is listed or falls in a range dropped by
+// IgnorePrivateIPs or IgnoreBogons.
+func (p *PrivateChecks) addrIgnored(addr netip.Addr) bool {
+	return p.ipListed(addr) || p.IgnorePrivateIPs && isPrivateIP(addr) || p.IgnoreBogons && isBogon(addr)
+}
+
+// prefixIgnored reports whether prefix lies within one of IgnoredCIDRs.
+func (p *PrivateChecks) prefixIgnored(prefix netip.Prefix) bool {
+	prefix = prefix.Masked()
+	for _, ignored := range p.IgnoredCIDRs {
+		if ignored.Bits() <= prefix.Bits() && ignored.Contains(prefix.Addr()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *PrivateChecks) ignoreCIDRs(prefixes ...netip.Prefix) {
+	for _, prefix := range prefixes {
+		p.IgnoredCIDRs = append(p.IgnoredCIDRs, prefix.Masked())
+	}
+}
+
+func (p *PrivateChecks) ignorePatterns(kind string, regexes ...*regexp.Regexp) {
+	if p.IgnoredPatterns == nil {
+		p.IgnoredPatterns = make(map[string][]*regexp.Regexp)
+	}
+	// Appending to a fresh slice keeps clones from sharing a backing array.
+	p.IgnoredPatterns[kind] = append(slices.Clip(p.IgnoredPatterns[kind]), regexes...)
+}
+
+// patternIgnored reports whether val matches one of the ignore patterns of
+// kind.
+func (p *PrivateChecks) patternIgnored(kind, val, cleanVal string) bool {
+	for _, regex := range p.IgnoredPatterns[kind] {
+		if regex.MatchString(val) || cleanVal != val && regex.MatchString(cleanVal) {
+			return true
+		}
+	}
+	return false
+}
+
+// HashAllowlist looks up known-good hashes, for example in NSRL or a
+// golden-image database. Known receives lowercase hex digests and must be
+// safe for concurrent use.
+type HashAllowlist interface {
+	Known(hash string) bool
+}
+
+func (p *PrivateChecks) ignoreHashes(hashes ...string) {
+	if p.IgnoredHashes == nil {
+		p.IgnoredHashes = make(map[string]struct{}, len(hashes))
+	}
+	for _, h := range hashes {
+		p.IgnoredHashes[strings.ToLower(h)] = struct{}{}
+	}
+}
+
+// hashIgnored reports whether the lowercase digest is known good.
+func (p *PrivateChecks) hashIgnored(hash string) bool {
+	if _, ok := p.IgnoredHashes[hash]; ok {
+		return true
+	}
+	return p.HashAllowlist != nil && p.HashAllowlist.Known(hash)
+}
+
+func validHashDigest(s string) bool {
+	switch len(s) {
+	case 32, 40, 64, 128:
+	default:
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isHexByte(s[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"net/netip"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestContextualizer_LoadIgnoreLists(t *testing.T) {
+	c := NewContextualizer()
+
+	n, err := c.LoadIgnoredDomains(strings.NewReader("# corporate\ncorp.example\n\n  .Partner.NET  # trailing comment\n"))
+	if err != nil || n != 2 {
+		t.Fatalf("LoadIgnoredDomains() = %d, %v", n, err)
+	}
+	n, err = c.LoadIgnoredEmails(strings.NewReader("soc@vendor.io\n#abuse@vendor.io\n"))
+	if err != nil || n != 1 {
+		t.Fatalf("LoadIgnoredEmails() = %d, %v", n, err)
+	}
+	n, err = c.LoadIgnoredIPs(strings.NewReader("8.8.8.8\n2001:db8::1\n"))
+	if err != nil || n != 2 {
+		t.Fatalf("LoadIgnoredIPs() = %d, %v", n, err)
+	}
+
+	got := c.ExtractAll("www.corp.example cdn.partner.net evil.com soc@vendor.io abuse@vendor.io " +
+		"8.8.8.8 1.1.1.1 2001:DB8::1 8.8.8.8:53")
+	if len(got["domain"]) != 2 || got["domain"][0].Value == "www.corp.example" {
+		t.Errorf("domain = %v", got["domain"])
+	}
+	if len(got["email"]) != 1 || got["email"][0].Value != "abuse@vendor.io" {
+		t.Errorf("email = %v", got["email"])
+	}
+	if len(got["ipv4"]) != 1 || got["ipv4"][0].Value != "1.1.1.1" {
+		t.Errorf("ipv4 = %v", got["ipv4"])
+	}
+	if got["ipv6"] != nil || got["ipport"] != nil {
+		t.Errorf("ignored addresses extracted: %v %v", got["ipv6"], got["ipport"])
+	}
+}
+
+func TestContextualizer_LoadIgnoredIPsInvalid(t *testing.T) {
+	c := NewContextualizer()
+	if _, err := c.LoadIgnoredIPs(strings.NewReader("8.8.8.8\nnot-an-ip\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("LoadIgnoredIPs() error = %v", err)
+	}
+	if len(c.Checks.IgnoredIPs) != 0 {
+		t.Errorf("failed load ignored %v", c.Checks.IgnoredIPs)
+	}
+}
+
+func TestContextualizer_IgnoredCIDRs(t *testing.T) {
+	c := NewContextualizer(WithIgnoredCIDRs(netip.MustParsePrefix("203.0.113.0/24")))
+	if err := c.AddExpression("cidr", `\b(\d{1,3}(?:\.\d{1,3}){3}/\d{1,2})\b`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.LoadIgnoredIPs(strings.NewReader("2001:db8::/32\n198.51.100.7\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	got := c.ExtractAll("203.0.113.9 and 203.0.113.9:8443 and 198.51.100.7, 198.51.100.8, 2001:db8:1::5, " +
+		"routes 203.0.113.128/25 203.0.0.0/16")
+	// The network address of 203.0.0.0/16 is outside the ignored /24.
+	if len(got["ipv4"]) != 2 || got["ipv4"][0].Value != "198.51.100.8" || got["ipv4"][1].Value != "203.0.0.0" {
+		t.Errorf("ipv4 = %v", got["ipv4"])
+	}
+	if got["ipport"] != nil || got["ipv6"] != nil {
+		t.Errorf("ignored ranges extracted: %v %v", got["ipport"], got["ipv6"])
+	}
+	if len(got["cidr"]) != 1 || got["cidr"][0].Value != "203.0.0.0/16" {
+		t.Errorf("cidr = %v", got["cidr"])
+	}
+
+	if _, err := c.LoadIgnoredIPs(strings.NewReader("10.0.0.0/33\n")); err == nil {
+		t.Error("LoadIgnoredIPs accepted a bad CIDR")
+	}
+}
+
+func TestContextualizer_IgnorePrivateAndBogons(t *testing.T) {
+	text := "fd00::1 fe80::1%eth0 2606:4700::1111 100.64.1.1 8.8.8.8 0.1.2.3 192.0.2.5:80 2001:db8::5"
+
+	got := NewContextualizer(WithIgnorePrivateIPs(true)).ExtractAll(text)
+	if len(got["ipv6"]) != 2 || got["ipv6"][0].Value != "2606:4700::1111" || got["ipv6"][1].Value != "2001:db8::5" {
+		t.Errorf("private ipv6 = %v", got["ipv6"])
+	}
+
+	got = NewContextualizer(WithIgnoreBogons(true)).ExtractAll(text)
+	if len(got["ipv6"]) != 1 || got["ipv6"][0].Value != "2606:4700::1111" {
+		t.Errorf("bogon ipv6 = %v", got["ipv6"])
+	}
+	if len(got["ipv4"]) != 1 || got["ipv4"][0].Value != "8.8.8.8" {
+		t.Errorf("bogon ipv4 = %v", got["ipv4"])
+	}
+	if got["ipport"] != nil {
+		t.Errorf("bogon ipport = %v", got["ipport"])
+	}
+}
+
+func TestContextualizer_IgnorePatterns(t *testing.T) {
+	c := NewContextualizer(WithIgnorePatterns("url", regexp.MustCompile(`/healthz\b`)))
+	if err := c.AddIgnorePattern("domain", `\.internal\.corp$`); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddIgnorePattern("domain", `(`); err == nil {
+		t.Error("AddIgnorePattern accepted a bad expression")
+	}
+
+	got := c.ExtractAll("probe http://svc.example.com/healthz and http://svc.example.com/login, " +
+		"hosts DB01.Internal.Corp and evil.com")
+	if len(got["url"]) != 1 || got["url"][0].Value != "http://svc.example.com/login" {
+		t.Errorf("url = %v", got["url"])
+	}
+	for _, m := range got["domain"] {
+		if m.Value == "db01.internal.corp" {
+			t.Errorf("ignored domain extracted: %v", m)
+		}
+	}
+
+	clone := c.Clone()
+	if err := clone.AddIgnorePattern("domain", `^evil\.com$`); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Checks.IgnoredPatterns["domain"]) != 1 {
+		t.Errorf("clone shares ignore patterns: %v", c.Checks.IgnoredPatterns)
+	}
+}
+
+type goldenImage map[string]bool
+
+func (g goldenImage) Known(hash string) bool { return g[hash] }
+
+func TestContextualizer_IgnoredHashes(t *testing.T) {
+	c := NewContextualizer(
+		WithIgnoredHashes("D41D8CD98F00B204E9800998ECF8427E"),
+		WithHashAllowlist(goldenImage{"da39a3ee5e6b4b0d3255bfef95601890afd80709": true}),
+	)
+	n, err := c.LoadIgnoredHashes(strings.NewReader("# golden\n900150983cd24fb0d6963f7d28e17f72\n"))
+	if err != nil || n != 1 {
+		t.Fatalf("LoadIgnoredHashes() = %d, %v", n, err)
+	}
+	if _, err := c.LoadIgnoredHashes(strings.NewReader("abc123\n")); err == nil {
+		t.Error("LoadIgnoredHashes accepted a short digest")
+	}
+
+	got := c.ExtractAll("d41d8cd98f00b204e9800998ecf8427e 900150983CD24FB0D6963F7D28E17F72 " +
+		"5d41402abc4b2a76b9719d911017c592 da39a3ee5e6b4b0d3255bfef95601890afd80709")
+	if len(got["md5"]) != 1 || got["md5"][0].Value != "5d41402abc4b2a76b9719d911017c592" {
+		t.Errorf("md5 = %v", got["md5"])
+	}
+	if got["sha1"] != nil {
+		t.Errorf("sha1 = %v", got["sha1"])
+	}
+}
@@ -0,0 +1,33 @@
+package parser
+
+import (
+	"iter"
+	"regexp"
+)
+
+// Matches returns an iterator over the matches ExtractAll would return.
+// Scanning happens while the caller ranges, and stops when it breaks.
+func (c *Contextualizer) Matches(text string) iter.Seq[Match] {
+	return func(yield func(Match) bool) {
+		c.scan(text, c.expressions(), c.Entropy, 0, yield)
+	}
+}
+
+// MatchesOf returns an iterator over the matches of the expression
+// registered under kind, including matches derived from them (such as
+// base_domain for domain). An unknown kind yields nothing.
+func (c *Contextualizer) MatchesOf(text, kind string) iter.Seq[Match] {
+	return func(yield func(Match) bool) {
+		regex, ok := c.expressions()[kind]
+		if !ok {
+			return
+		}
+		c.scan(text, map[string]*regexp.Regexp{kind: regex}, nil, 0, yield)
+	}
+}
+
+// ExtractFunc calls fn for every match ExtractAll would return, as soon as
+// it is found, and stops scanning as soon as fn returns false.
+func (c *Contextualizer) ExtractFunc(text string, fn func(Match) bool) {
+	c.scan(text, c.expressions(), c.Entropy, 0, fn)
+}
@@ -0,0 +1,86 @@
+package parser
+
+import "testing"
+
+func TestContextualizer_Matches(t *testing.T) {
+	c := NewContextualizer()
+	text := "Hash d41d8cd98f00b204e9800998ecf8427e from 8.8.8.8 and 1.1.1.1 via sub.evil.com"
+
+	var n int
+	for range c.Matches(text) {
+		n++
+	}
+	var want int
+	for _, matches := range c.ExtractAll(text) {
+		want += len(matches)
+	}
+	if n != want {
+		t.Errorf("Matches() yielded %d matches, ExtractAll returned %d", n, want)
+	}
+
+	var ips []string
+	for m := range c.MatchesOf(text, "ipv4") {
+		ips = append(ips, m.Value)
+		break
+	}
+	if len(ips) != 1 || ips[0] != "8.8.8.8" {
+		t.Errorf("MatchesOf() = %v", ips)
+	}
+
+	for m := range c.MatchesOf(text, "nope") {
+		t.Errorf("MatchesOf(unknown) yielded %v", m)
+	}
+}
+
+func TestContextualizer_ExtractFunc(t *testing.T) {
+	c := NewContextualizer()
+
+	var calls int
+	c.ExtractFunc("8.8.8.8 1.1.1.1 9.9.9.9 d41d8cd98f00b204e9800998ecf8427e", func(Match) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Errorf("ExtractFunc() kept calling after false: %d calls", calls)
+	}
+
+	found := false
+	c.ExtractFunc("nothing to see here", func(Match) bool {
+		found = true
+		return false
+	})
+	if found {
+		t.Errorf("ExtractFunc() found a match in plain prose")
+	}
+}
+
+func TestContextualizer_MatchesBreakBeforeBase64(t *testing.T) {
+	c := NewContextualizer(WithEntropy(EntropyConfig{}), WithBase64Decoding(Base64Config{}))
+	// The blob decodes to a line holding http://evil.example/payload/stage2.bin.
+	text := "token Zx9Qk2Lm8Vr4Tb7Wn1Ys5Hd3Pf6Jc0Ga then " +
+		"bG9hZGVyIGZldGNoZXMgaHR0cDovL2V2aWwuZXhhbXBsZS9wYXlsb2FkL3N0YWdlMi5iaW4gbmV4dA=="
+
+	var kinds []string
+	for m := range c.Matches(text) {
+		kinds = append(kinds, m.Type)
+		if m.Type == "secret_candidate" {
+			break
+		}
+	}
+	if len(kinds) == 0 || kinds[len(kinds)-1] != "secret_candidate" {
+		t.Fatalf("Matches() = %v, want to stop at a secret_candidate", kinds)
+	}
+
+	var calls int
+	c.ExtractFunc(text, func(m Match) bool {
+		calls++
+		return m.Type != "secret_candidate"
+	})
+	if calls != len(kinds) {
+		t.Errorf("ExtractFunc called fn %d times after it returned false, want %d calls", calls, len(kinds))
+	}
+	c.Workers = 4
+	if got := c.ExtractAll(text); got["secret_candidate"] == nil || got["url"] == nil {
+		t.Errorf("ExtractAll() with workers = %v", got)
+	}
+}
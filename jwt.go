@@ -0,0 +1,35 @@
+package parser
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+// decodeJWT decodes the header and payload segments of a token. It reports
+// false unless the header is a JSON object naming an algorithm, which keeps
+// random dotted base64 out of the jwt results.
+func decodeJWT(token string) (header, payload string, ok bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", "", false
+	}
+
+	h, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[0], "="))
+	if err != nil {
+		return "", "", false
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(h, &fields); err != nil {
+		return "", "", false
+	}
+	if _, exists := fields["alg"]; !exists {
+		return "", "", false
+	}
+
+	p, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return "", "", false
+	}
+	return string(h), string(p), true
+}
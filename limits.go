@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+// ErrTruncated is sent on the error channel of ExtractStream when a limit
+// cut the scan short.
+var ErrTruncated = errors.New("parser: extraction truncated by limits")
+
+// Limits bounds the work a single extraction may do, so that a hostile or
+// pathological document cannot produce millions of matches. Zero values
+// mean no limit.
+type Limits struct {
+	// MaxInputSize scans only the first MaxInputSize bytes of the input.
+	MaxInputSize int `json:"max_input_size,omitempty"`
+	// MaxMatches stops the scan once that many matches have been kept.
+	MaxMatches int `json:"max_matches,omitempty"`
+	// MaxPerKind caps the matches kept for any single type.
+	MaxPerKind int `json:"max_per_kind,omitempty"`
+	// PerKind caps individual types, overriding MaxPerKind.
+	PerKind map[string]int `json:"per_kind,omitempty"`
+}
+
+// limiter applies Limits to one extraction and records whether anything
+// was cut.
+type limiter struct {
+	limits    *Limits
+	total     int
+	perKind   map[string]int
+	truncated bool
+}
+
+func (c *Contextualizer) newLimiter() *limiter {
+	return &limiter{limits: c.Limits}
+}
+
+// clip shortens text to MaxInputSize, backing off to a rune boundary.
+func (l *limiter) clip(text string) string {
+	if l.limits == nil || l.limits.MaxInputSize <= 0 || len(text) <= l.limits.MaxInputSize {
+		return text
+	}
+	l.truncated = true
+	end := l.limits.MaxInputSize
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	return text[:end]
+}
+
+// admit reports whether m may be kept and whether the scan should go on.
+func (l *limiter) admit(m Match) (keep, more bool) {
+	if l.limits == nil {
+		return true, true
+	}
+	if l.limits.MaxMatches > 0 && l.total >= l.limits.MaxMatches {
+		l.truncated = true
+		return false, false
+	}
+	if n := l.kindCap(m.Type); n > 0 {
+		if l.perKind == nil {
+			l.perKind = make(map[string]int)
+		}
+		if l.perKind[m.Type] >= n {
+			l.truncated = true
+			return false, true
+		}
+		l.perKind[m.Type]++
+	}
+	l.total++
+	return true, true
+}
+
+func (l *limiter) kindCap(typ string) int {
+	if n, ok := l.limits.PerKind[typ]; ok {
+		return n
+	}
+	return l.limits.MaxPerKind
+}
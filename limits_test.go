@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestContextualizer_Limits(t *testing.T) {
+	text := "1.1.1.1 2.2.2.2 3.3.3.3 4.4.4.4 evil.com bad.org worse.net"
+
+	tests := []struct {
+		name   string
+		limits Limits
+		check  func(t *testing.T, r *Result)
+	}{
+		{
+			name:   "no limits",
+			limits: Limits{},
+			check: func(t *testing.T, r *Result) {
+				if r.Truncated || len(r.Matches["ipv4"]) != 4 {
+					t.Errorf("got %v, truncated %v", r.Matches, r.Truncated)
+				}
+			},
+		},
+		{
+			name:   "max matches",
+			limits: Limits{MaxMatches: 3},
+			check: func(t *testing.T, r *Result) {
+				if !r.Truncated || r.Len() != 3 {
+					t.Errorf("got %d matches, truncated %v", r.Len(), r.Truncated)
+				}
+			},
+		},
+		{
+			name:   "per kind",
+			limits: Limits{MaxPerKind: 2, PerKind: map[string]int{"domain": 1}},
+			check: func(t *testing.T, r *Result) {
+				if !r.Truncated || len(r.Matches["ipv4"]) != 2 || len(r.Matches["domain"]) != 1 {
+					t.Errorf("got %v, truncated %v", r.Matches, r.Truncated)
+				}
+			},
+		},
+		{
+			name:   "input size",
+			limits: Limits{MaxInputSize: 10},
+			check: func(t *testing.T, r *Result) {
+				if !r.Truncated || len(r.Matches["ipv4"]) != 1 || len(r.Matches["domain"]) != 0 {
+					t.Errorf("got %v, truncated %v", r.Matches, r.Truncated)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewContextualizer(WithLimits(tt.limits))
+			r := NewResult()
+			c.ExtractInto(text, r)
+			tt.check(t, r)
+		})
+	}
+}
+
+func TestLimiter_ClipRuneBoundary(t *testing.T) {
+	l := &limiter{limits: &Limits{MaxInputSize: 2}}
+	if got := l.clip("aé"); got != "a" || !l.truncated {
+		t.Errorf("clip() = %q, truncated %v", got, l.truncated)
+	}
+}
+
+func TestContextualizer_LimitsParallel(t *testing.T) {
+	c := NewContextualizer(WithLimits(Limits{MaxMatches: 2}), WithWorkers(4))
+	got := c.ExtractAll("1.1.1.1 2.2.2.2 3.3.3.3 evil.com")
+	var n int
+	for _, matches := range got {
+		n += len(matches)
+	}
+	if n != 2 {
+		t.Errorf("ExtractAll() kept %d matches, want 2", n)
+	}
+}
+
+func TestContextualizer_LimitsStream(t *testing.T) {
+	c := NewContextualizer(WithLimits(Limits{MaxMatches: 1}))
+	matches, errc := c.ExtractStream(context.Background(), "1.1.1.1 2.2.2.2")
+	var n int
+	for range matches {
+		n++
+	}
+	if err := <-errc; !errors.Is(err, ErrTruncated) || n != 1 {
+		t.Errorf("got %d matches, err %v", n, err)
+	}
+}
@@ -0,0 +1,137 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// mispWarninglist is the JSON layout of a MISP warninglist.
+type mispWarninglist struct {
+	Name               string   `json:"name"`
+	Type               string   `json:"type"`
+	List               []string `json:"list"`
+	MatchingAttributes []string `json:"matching_attributes"`
+}
+
+// mispKinds maps MISP attribute types to the kinds they cover.
+var mispKinds = map[string][]string{
+	"ip-src":      {"ipv4", "ipv6", "ipport"},
+	"ip-dst":      {"ipv4", "ipv6", "ipport"},
+	"ip-src|port": {"ipport"},
+	"ip-dst|port": {"ipport"},
+	"domain|ip":   {"domain", "ipv4", "ipv6"},
+	"domain":      {"domain"},
+	"hostname":    {"domain"},
+	"url":         {"url"},
+	"link":        {"url"},
+	"uri":         {"url"},
+	"email":       {"email"},
+	"email-src":   {"email"},
+	"email-dst":   {"email"},
+	"md5":         {"md5"},
+	"sha1":        {"sha1"},
+	"sha256":      {"sha256"},
+	"sha512":      {"sha512"},
+}
+
+// LoadMISPWarninglist imports a MISP warninglist (the list.json of a
+// misp-warninglists entry) into the ignore lists and returns the number of
+// entries imported.
+//
+// Lists of type "cidr", "hostname" and "string" are routed by entry: IP
+// addresses and ranges go to IgnoredIPs and IgnoredCIDRs, email addresses
+// to IgnoredEmails, hash digests to IgnoredHashes and anything else to
+// IgnoredDomains, which also covers subdomains. Lists of type "substring"
+// and "regex" become ignore patterns for the kinds named by the list's
+// matching_attributes.
+func (c *Contextualizer) LoadMISPWarninglist(r io.Reader) (int, error) {
+	var wl mispWarninglist
+	if err := json.NewDecoder(r).Decode(&wl); err != nil {
+		return 0, fmt.Errorf("decoding MISP warninglist: %w", err)
+	}
+
+	switch wl.Type {
+	case "cidr", "hostname", "string":
+		return c.importMISPValues(wl)
+	case "substring", "regex":
+		return c.importMISPPatterns(wl)
+	default:
+		return 0, fmt.Errorf("MISP warninglist %q: unsupported type %q", wl.Name, wl.Type)
+	}
+}
+
+func (c *Contextualizer) importMISPValues(wl mispWarninglist) (int, error) {
+	entries := make([]string, 0, len(wl.List))
+	for _, entry := range wl.List {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if wl.Type == "cidr" && !isIPOrCIDR(entry) {
+			return 0, fmt.Errorf("MISP warninglist %q: invalid CIDR %q", wl.Name, entry)
+		}
+		entries = append(entries, entry)
+	}
+
+	c.updateChecks(func(p *PrivateChecks) {
+		for _, entry := range entries {
+			switch {
+			case isIPOrCIDR(entry):
+				if prefix, _ := parseIPOrCIDR(entry); strings.Contains(entry, "/") {
+					p.ignoreCIDRs(prefix)
+				} else {
+					p.ignoreIPs(entry)
+				}
+			case strings.Contains(entry, "@"):
+				p.ignoreEmails(entry)
+			case validHashDigest(entry):
+				p.ignoreHashes(entry)
+			default:
+				p.ignoreDomains(strings.TrimSuffix(entry, "."))
+			}
+		}
+	})
+	return len(entries), nil
+}
+
+func (c *Contextualizer) importMISPPatterns(wl mispWarninglist) (int, error) {
+	regexes := make([]*regexp.Regexp, 0, len(wl.List))
+	for _, entry := range wl.List {
+		if entry == "" {
+			continue
+		}
+		pattern := entry
+		if wl.Type == "substring" {
+			pattern = "(?i)" + regexp.QuoteMeta(entry)
+		}
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return 0, fmt.Errorf("MISP warninglist %q: %w", wl.Name, err)
+		}
+		regexes = append(regexes, regex)
+	}
+
+	kinds := make(map[string]struct{})
+	for _, attr := range wl.MatchingAttributes {
+		for _, kind := range mispKinds[attr] {
+			kinds[kind] = struct{}{}
+		}
+	}
+	if len(kinds) == 0 {
+		return 0, fmt.Errorf("MISP warninglist %q: no supported matching_attributes", wl.Name)
+	}
+	c.updateChecks(func(p *PrivateChecks) {
+		for kind := range kinds {
+			p.ignorePatterns(kind, regexes...)
+		}
+	})
+	return len(regexes), nil
+}
+
+func isIPOrCIDR(s string) bool {
+	_, err := parseIPOrCIDR(s)
+	return err == nil
+}
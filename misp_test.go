@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContextualizer_LoadMISPWarninglist(t *testing.T) {
+	lists := []string{
+		`{"name": "List of known public DNS resolvers", "type": "cidr",
+		  "list": ["8.8.8.8", "2001:4860:4860::8888", "1.1.1.0/24"],
+		  "matching_attributes": ["ip-src", "ip-dst"]}`,
+		`{"name": "Top 1000 domains", "type": "string",
+		  "list": ["google.com", "microsoft.com."],
+		  "matching_attributes": ["hostname", "domain"]}`,
+		`{"name": "Second level TLDs", "type": "hostname",
+		  "list": [".amazonaws.com"],
+		  "matching_attributes": ["hostname", "domain", "url"]}`,
+		`{"name": "Health checks", "type": "substring",
+		  "list": ["/healthz"],
+		  "matching_attributes": ["url"]}`,
+		`{"name": "Internal", "type": "regex",
+		  "list": ["\\.internal\\.corp$"],
+		  "matching_attributes": ["domain"]}`,
+	}
+	c := NewContextualizer()
+	for _, list := range lists {
+		if _, err := c.LoadMISPWarninglist(strings.NewReader(list)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := c.ExtractAll("8.8.8.8 1.1.1.1 9.9.9.9 www.google.com login.microsoft.com s3.amazonaws.com " +
+		"db.internal.corp evil.com http://evil.net/HEALTHZ http://evil.net/gate")
+	if len(got["ipv4"]) != 1 || got["ipv4"][0].Value != "9.9.9.9" {
+		t.Errorf("ipv4 = %v", got["ipv4"])
+	}
+	if len(got["url"]) != 1 || got["url"][0].Value != "http://evil.net/gate" {
+		t.Errorf("url = %v", got["url"])
+	}
+	for _, m := range got["domain"] {
+		if m.Value != "evil.com" && m.Value != "evil.net" {
+			t.Errorf("warninglisted domain extracted: %v", m)
+		}
+	}
+}
+
+func TestContextualizer_LoadMISPWarninglistErrors(t *testing.T) {
+	c := NewContextualizer()
+	for _, list := range []string{
+		`not json`,
+		`{"name": "bad", "type": "cidr", "list": ["8.8.8.8", "nope"]}`,
+		`{"name": "bad", "type": "regex", "list": ["("], "matching_attributes": ["domain"]}`,
+		`{"name": "bad", "type": "regex", "list": ["x"], "matching_attributes": ["filename"]}`,
+		`{"name": "bad", "type": "other", "list": ["x"]}`,
+	} {
+		if _, err := c.LoadMISPWarninglist(strings.NewReader(list)); err == nil {
+			t.Errorf("LoadMISPWarninglist(%s) succeeded", list)
+		}
+	}
+	if len(c.Checks.IgnoredIPs) != 0 {
+		t.Errorf("failed import ignored %v", c.Checks.IgnoredIPs)
+	}
+}
@@ -0,0 +1,192 @@
+package parser
+
+import (
+	"net/netip"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// Normalizer returns the canonical form of a value of one kind.
+type Normalizer func(val string) string
+
+// normalize returns the canonical form of val, using c.Normalizers before
+// the built-in Normalize.
+func (c *Contextualizer) normalize(kind, val string) string {
+	if n, ok := c.Normalizers[kind]; ok {
+		return n(val)
+	}
+	val = Normalize(kind, val)
+	if kind == "email" && (c.StripPlusAddressing || c.GmailDots) {
+		val = canonicalEmail(val, c.StripPlusAddressing, c.GmailDots)
+	}
+	return val
+}
+
+// dedupKey is the key under which a value is deduplicated: its lowercased
+// form, or its lowercased canonical form when Normalize is set so that
+// different spellings of an indicator count once.
+func (c *Contextualizer) dedupKey(kind, val, cleanVal string) string {
+	key := cleanVal
+	if c.Normalize {
+		key = strings.ToLower(c.normalize(kind, val))
+	}
+	if kind == "url" && c.EquivalentURLs {
+		key = equivalentURL(key)
+	}
+	return key
+}
+
+// Normalize returns the canonical form of a value of the given kind:
+// lowercase hex for hashes, the compressed form of IPv6 addresses, dotted
+// quads for obfuscated IPv4 addresses, lowercase punycode for domains and
+// the domain of emails, and URLs with their scheme and host lowercased and
+// the host in punycode. Values of other kinds, and values that don't
+// parse, are returned unchanged.
+func Normalize(kind, val string) string {
+	switch kind {
+	case "md5", "sha1", "sha256", "sha512", "jarm":
+		return strings.ToLower(val)
+	case "ipv4", "ipv6":
+		if addr, err := netip.ParseAddr(val); err == nil {
+			return addr.String()
+		}
+	case "ipport":
+		if ap, err := netip.ParseAddrPort(val); err == nil {
+			return ap.String()
+		}
+	case "obfuscated_ipv4":
+		if addr, _, ok := parseObfuscatedIPv4(val); ok {
+			return addr.String()
+		}
+	case "timestamp":
+		if t, _, ok := parseTimestamp(val); ok {
+			return formatTimestamp(t)
+		}
+	case "domain", "base_domain":
+		return normalizeDomain(val)
+	case "email":
+		if local, domain, ok := strings.Cut(val, "@"); ok {
+			return strings.ToLower(local) + "@" + normalizeDomain(domain)
+		}
+	case "url":
+		return normalizeURL(val)
+	}
+	return val
+}
+
+// normalizeDomain lowercases domain and converts internationalized labels
+// to punycode, leaving it merely lowercased when it is not a valid IDN.
+func normalizeDomain(domain string) string {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	if ascii, err := idna.Lookup.ToASCII(domain); err == nil {
+		return ascii
+	}
+	return domain
+}
+
+// asciiDomain is the lowercase ASCII form of domain under which the ignore
+// lists store and look up domains, so an entry matches whether it was
+// written in Unicode or as xn-- punycode.
+func asciiDomain(domain string) string {
+	if isASCII(domain) {
+		return strings.TrimSuffix(strings.ToLower(domain), ".")
+	}
+	return normalizeDomain(domain)
+}
+
+// asciiEmail is asciiDomain for the domain of an email address.
+func asciiEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || isASCII(domain) {
+		return strings.ToLower(email)
+	}
+	return strings.ToLower(local) + "@" + normalizeDomain(domain)
+}
+
+// isIDN reports whether val is an internationalized domain, email or URL,
+// whose punycode form is always stored in Match.Normalized.
+func isIDN(kind, val string) bool {
+	switch kind {
+	case "domain", "email", "url":
+		return !isASCII(val)
+	}
+	return false
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeURL lowercases the scheme and host of a URL and converts the
+// host to punycode. The path, query and fragment are case-sensitive and
+// kept as written.
+func normalizeURL(val string) string {
+	scheme, rest, ok := strings.Cut(val, "://")
+	if !ok {
+		return val
+	}
+	end := strings.IndexAny(rest, "/?#")
+	if end < 0 {
+		end = len(rest)
+	}
+	authority, tail := rest[:end], rest[end:]
+
+	var userinfo string
+	if i := strings.LastIndexByte(authority, '@'); i >= 0 {
+		userinfo, authority = authority[:i+1], authority[i+1:]
+	}
+	host, port := authority, ""
+	if i := strings.LastIndexByte(authority, ':'); i >= 0 && !strings.HasSuffix(authority, "]") {
+		host, port = authority[:i], authority[i:]
+	}
+	if strings.HasPrefix(host, "[") {
+		host = strings.ToLower(host)
+	} else {
+		host = normalizeDomain(host)
+	}
+	return strings.ToLower(scheme) + "://" + userinfo + host + port + tail
+}
+
+// gmailDomains are the domains whose mailboxes ignore dots in the local
+// part.
+var gmailDomains = map[string]bool{
+	"gmail.com":      true,
+	"googlemail.com": true,
+}
+
+// canonicalEmail removes the plus tag and, for Gmail addresses, the dots
+// from the local part of a normalized email address.
+func canonicalEmail(email string, stripPlus, gmailDots bool) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok {
+		return email
+	}
+	if stripPlus {
+		local, _, _ = strings.Cut(local, "+")
+	}
+	if gmailDots && gmailDomains[domain] {
+		local = strings.ReplaceAll(local, ".", "")
+	}
+	return local + "@" + domain
+}
+
+// emailParts splits an email address into the parts attached to email
+// matches: the local part, the domain and, with plus addressing, the tag.
+func emailParts(email string) map[string]string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok {
+		return nil
+	}
+	parts := map[string]string{"local_part": local, "domain": strings.ToLower(domain)}
+	if _, tag, ok := strings.Cut(local, "+"); ok {
+		parts["tag"] = tag
+	}
+	return parts
+}
@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		kind, val, want string
+	}{
+		{"md5", "D41D8CD98F00B204E9800998ECF8427E", "d41d8cd98f00b204e9800998ecf8427e"},
+		{"ipv6", "2001:0DB8:0000:0000:0000:0000:0000:0001", "2001:db8::1"},
+		{"ipport", "[2001:DB8::1]:443", "[2001:db8::1]:443"},
+		{"domain", "Bücher.Example.", "xn--bcher-kva.example"},
+		{"email", "Bob@Bücher.de", "bob@xn--bcher-kva.de"},
+		{"url", "HTTPS://user@Bücher.DE:8443/Path?Q=1", "https://user@xn--bcher-kva.de:8443/Path?Q=1"},
+		{"url", "http://[2001:DB8::1]/X", "http://[2001:db8::1]/X"},
+		{"filename", "Report.PDF", "Report.PDF"},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.kind, tt.val); got != tt.want {
+			t.Errorf("Normalize(%q, %q) = %q, want %q", tt.kind, tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestContextualizer_Normalize(t *testing.T) {
+	c := NewContextualizer(WithNormalization())
+
+	got := c.ExtractAll("see 2001:db8::1 and 2001:0db8:0:0:0:0:0:1, http://bücher.de/a and http://xn--bcher-kva.de/a")
+	if m := got["ipv6"]; len(m) != 1 || m[0].Normalized != "2001:db8::1" {
+		t.Errorf("ipv6 = %+v", m)
+	}
+	if m := got["url"]; len(m) != 1 || m[0].Value != "http://bücher.de/a" || m[0].Normalized != "http://xn--bcher-kva.de/a" {
+		t.Errorf("url = %+v", m)
+	}
+
+	c = NewContextualizer(WithNormalizer("filename", func(val string) string { return "x" }))
+	if m := c.GetMatches("a.txt", "filename", c.Expressions["filename"]); len(m) != 1 || m[0].Normalized != "x" {
+		t.Errorf("filename = %+v", m)
+	}
+}
+
+func TestContextualizer_EmailNormalization(t *testing.T) {
+	c := NewContextualizer(WithPlusAddressingStripped(), WithGmailDotsIgnored())
+
+	got := c.ExtractAll("from bob+spam@gmail.com, b.ob@GMail.com and b.ob@example.com")["email"]
+	if len(got) != 2 {
+		t.Fatalf("email = %+v", got)
+	}
+	if got[0].Normalized != "bob@gmail.com" || got[1].Normalized != "b.ob@example.com" {
+		t.Errorf("Normalized = %q, %q", got[0].Normalized, got[1].Normalized)
+	}
+	want := map[string]string{"local_part": "bob+spam", "domain": "gmail.com", "tag": "spam"}
+	if !reflect.DeepEqual(got[0].Metadata, want) {
+		t.Errorf("Metadata = %v, want %v", got[0].Metadata, want)
+	}
+}
+
+func TestContextualizer_IDN(t *testing.T) {
+	c := NewContextualizer()
+
+	got := c.ExtractAll("visit bücher.example or пример.рф, mail info@bücher.example, and xn--e1afmkfd.xn--p1ai")
+	domains := got["domain"]
+	want := map[string]string{
+		"bücher.example":        "xn--bcher-kva.example",
+		"пример.рф":             "xn--e1afmkfd.xn--p1ai",
+		"xn--e1afmkfd.xn--p1ai": "",
+	}
+	if len(domains) != len(want) {
+		t.Fatalf("domain = %+v", domains)
+	}
+	for _, m := range domains {
+		if norm, ok := want[m.Value]; !ok || m.Normalized != norm {
+			t.Errorf("domain %q: Normalized = %q, want %q", m.Value, m.Normalized, norm)
+		}
+	}
+	if m := got["email"]; len(m) != 1 || m[0].Normalized != "info@xn--bcher-kva.example" {
+		t.Errorf("email = %+v", m)
+	}
+}
+
+func TestContextualizer_IDNBoundary(t *testing.T) {
+	c := NewContextualizer()
+	got := c.ExtractAll(`run.ps1 main.go2 lib.so6 user.name1 ops@corp.io1 C:\Tools\run.ps1 \\fs01\share\lib.so6`)
+	if got["domain"] != nil || got["email"] != nil {
+		t.Errorf("domain = %v, email = %v, want none", got["domain"], got["email"])
+	}
+
+	got = c.ExtractAll("пример.рф. and пример.рф1")
+	if d := values(got["domain"]); !reflect.DeepEqual(d, []string{"domain:пример.рф"}) {
+		t.Errorf("domain = %v, want [domain:пример.рф]", d)
+	}
+}
+
+func TestPrivateChecks_IDNIgnored(t *testing.T) {
+	tests := []struct {
+		entry, domain string
+	}{
+		{"bücher.example", "www.xn--bcher-kva.example"},
+		{"xn--bcher-kva.example", "shop.bücher.example"},
+		{"BÜCHER.example", "bücher.example"},
+	}
+	for _, tt := range tests {
+		c := NewContextualizer(WithIgnoredDomains(tt.entry))
+		if !c.checks().domainIgnored(tt.domain) {
+			t.Errorf("%q not ignored by %q", tt.domain, tt.entry)
+		}
+	}
+
+	c := NewContextualizer(WithIgnoredEmails("info@bücher.example"))
+	if got := c.ExtractAll("from info@xn--bcher-kva.example")["email"]; got != nil {
+		t.Errorf("email = %+v", got)
+	}
+}
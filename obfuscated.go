@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// parseObfuscatedIPv4 decodes an IPv4 address written the way inet_aton
+// accepts it but people don't: a single 32-bit number in decimal, octal or
+// hex (2130706433, 017700000001, 0x7f000001), or four dotted parts of
+// which at least one is octal or hex (0177.0.0.1, 0x7f.0.0.1). It returns
+// the address and the encoding, "decimal", "octal", "hex" or "dotted".
+// Plain dotted decimal is left to the ipv4 kind.
+func parseObfuscatedIPv4(s string) (netip.Addr, string, bool) {
+	if !strings.Contains(s, ".") {
+		n, encoding, ok := parseInetNumber(s, 32)
+		// Small numbers are far more likely counters or IDs than 0.x.x.x
+		// addresses.
+		if !ok || n < 1<<24 {
+			return netip.Addr{}, "", false
+		}
+		return netip.AddrFrom4([4]byte{byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)}), encoding, true
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) != 4 {
+		return netip.Addr{}, "", false
+	}
+	var octets [4]byte
+	obfuscated := false
+	for i, part := range parts {
+		n, encoding, ok := parseInetNumber(part, 8)
+		if !ok {
+			return netip.Addr{}, "", false
+		}
+		octets[i] = byte(n)
+		obfuscated = obfuscated || encoding != "decimal"
+	}
+	if !obfuscated {
+		return netip.Addr{}, "", false
+	}
+	return netip.AddrFrom4(octets), "dotted", true
+}
+
+// parseInetNumber parses a number of at most bits bits in the notation of
+// inet_aton: 0x for hex, a leading 0 for octal, decimal otherwise.
+func parseInetNumber(s string, bits int) (uint64, string, bool) {
+	base, encoding := 10, "decimal"
+	switch {
+	case len(s) > 2 && (s[:2] == "0x" || s[:2] == "0X"):
+		s, base, encoding = s[2:], 16, "hex"
+	case len(s) > 1 && s[0] == '0':
+		s, base, encoding = s[1:], 8, "octal"
+	}
+	n, err := strconv.ParseUint(s, base, bits)
+	return n, encoding, err == nil
+}
@@ -0,0 +1,42 @@
+package parser
+
+import "testing"
+
+func TestParseObfuscatedIPv4(t *testing.T) {
+	tests := []struct {
+		in, want, encoding string
+	}{
+		{"2130706433", "127.0.0.1", "decimal"},
+		{"0x7f000001", "127.0.0.1", "hex"},
+		{"017700000001", "127.0.0.1", "octal"},
+		{"0177.0.0.1", "127.0.0.1", "dotted"},
+		{"0x7f.0x0.0.0x1", "127.0.0.1", "dotted"},
+		{"10.0.0.1", "", ""},
+		{"12345", "", ""},
+		{"0x1ff.0.0.1", "", ""},
+		{"0999.0.0.1", "", ""},
+	}
+	for _, tt := range tests {
+		addr, encoding, ok := parseObfuscatedIPv4(tt.in)
+		if ok != (tt.want != "") || ok && (addr.String() != tt.want || encoding != tt.encoding) {
+			t.Errorf("parseObfuscatedIPv4(%q) = %v, %q, %v, want %q, %q", tt.in, addr, encoding, ok, tt.want, tt.encoding)
+		}
+	}
+}
+
+func TestContextualizer_ObfuscatedIPv4(t *testing.T) {
+	c := NewContextualizer()
+	if err := c.EnableProfile("obfuscation"); err != nil {
+		t.Fatalf("EnableProfile() error = %v", err)
+	}
+
+	got := c.ExtractAll("beacon to http://0x5db8d822/gate.php, fallback ip: 1572395042, build 20240101 of 3221225985 items")
+	obf := got["obfuscated_ipv4"]
+	if len(obf) != 2 || obf[0].Value != "0x5db8d822" || obf[0].Metadata["encoding"] != "hex" || obf[1].Value != "1572395042" {
+		t.Fatalf("obfuscated_ipv4 = %+v", obf)
+	}
+	ips := got["ipv4"]
+	if len(ips) != 2 || ips[0].Value != "93.184.216.34" || ips[0].Parent != "0x5db8d822" || ips[1].Value != "93.184.216.34" {
+		t.Errorf("ipv4 = %+v", ips)
+	}
+}
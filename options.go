@@ -0,0 +1,457 @@
+package parser
+
+import (
+	"io"
+	"net/netip"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Option configures a Contextualizer in NewContextualizer.
+type Option func(*Contextualizer)
+
+// WithIgnorePrivateIPs drops private, loopback and link-local addresses.
+func WithIgnorePrivateIPs(ignore bool) Option {
+	return func(c *Contextualizer) {
+		c.Checks.IgnorePrivateIPs = ignore
+	}
+}
+
+// WithIgnoredDomains ignores the given domains and all of their subdomains.
+func WithIgnoredDomains(domains ...string) Option {
+	return func(c *Contextualizer) {
+		c.Checks.ignoreDomains(domains...)
+	}
+}
+
+// WithIgnoredEmails ignores the given email addresses.
+func WithIgnoredEmails(emails ...string) Option {
+	return func(c *Contextualizer) {
+		c.Checks.ignoreEmails(emails...)
+	}
+}
+
+// WithIgnoredCIDRs ignores addresses within the given ranges.
+func WithIgnoredCIDRs(prefixes ...netip.Prefix) Option {
+	return func(c *Contextualizer) {
+		c.Checks.ignoreCIDRs(prefixes...)
+	}
+}
+
+// WithIgnorePatterns drops matches of kind whose value matches any of
+// regexes (see AddIgnorePattern).
+func WithIgnorePatterns(kind string, regexes ...*regexp.Regexp) Option {
+	return func(c *Contextualizer) {
+		c.Checks.ignorePatterns(kind, regexes...)
+	}
+}
+
+// WithIgnoredHashes ignores the given md5, sha1, sha256 or sha512 digests.
+func WithIgnoredHashes(hashes ...string) Option {
+	return func(c *Contextualizer) {
+		c.Checks.ignoreHashes(hashes...)
+	}
+}
+
+// WithHashAllowlist drops hash matches that allowlist knows.
+func WithHashAllowlist(allowlist HashAllowlist) Option {
+	return func(c *Contextualizer) {
+		c.Checks.HashAllowlist = allowlist
+	}
+}
+
+// WithTopDomainAllowlist ignores the first n domains of the popularity
+// list in r (see LoadTopDomains). Options cannot fail, so a read error
+// keeps whatever was loaded before it; call LoadTopDomains to see errors.
+func WithTopDomainAllowlist(r io.Reader, n int) Option {
+	return func(c *Contextualizer) {
+		c.LoadTopDomains(r, n)
+	}
+}
+
+// WithIgnoreBogons drops reserved and documentation addresses, see
+// PrivateChecks.IgnoreBogons.
+func WithIgnoreBogons(ignore bool) Option {
+	return func(c *Contextualizer) {
+		c.Checks.IgnoreBogons = ignore
+	}
+}
+
+// WithIgnoreLocalMACs drops locally administered and multicast MACs.
+func WithIgnoreLocalMACs(ignore bool) Option {
+	return func(c *Contextualizer) {
+		c.Checks.IgnoreLocalMACs = ignore
+	}
+}
+
+// WithExpressions adds expressions, replacing built-in ones of the same kind.
+func WithExpressions(exprs map[string]*regexp.Regexp) Option {
+	return func(c *Contextualizer) {
+		for kind, regex := range exprs {
+			c.Expressions[kind] = regex
+		}
+	}
+}
+
+// WithKinds restricts the Contextualizer to the listed kinds. It applies
+// after all other options, so it also filters expressions they added.
+func WithKinds(kinds ...string) Option {
+	return func(c *Contextualizer) {
+		if c.onlyKinds == nil {
+			c.onlyKinds = make(map[string]struct{}, len(kinds))
+		}
+		for _, k := range kinds {
+			c.onlyKinds[k] = struct{}{}
+		}
+	}
+}
+
+// WithDisabledKinds disables the listed kinds (see DisableKind), e.g. the
+// noisy filepath and filename extractors, while keeping their expressions.
+func WithDisabledKinds(kinds ...string) Option {
+	return func(c *Contextualizer) {
+		for _, k := range kinds {
+			c.DisableKind(k)
+		}
+	}
+}
+
+// WithDefanged enables recognition of defanged indicators (see
+// Contextualizer.MatchDefanged).
+func WithDefanged() Option {
+	return func(c *Contextualizer) {
+		c.MatchDefanged = true
+	}
+}
+
+// WithDefangOutput defangs every emitted value.
+func WithDefangOutput() Option {
+	return func(c *Contextualizer) {
+		c.DefangOutput = true
+	}
+}
+
+// WithKnownTLDs drops domain matches without an ICANN public suffix.
+func WithKnownTLDs() Option {
+	return func(c *Contextualizer) {
+		c.RequireKnownTLD = true
+	}
+}
+
+// WithGitCommits reports sha1 values in git context as git_commit.
+func WithGitCommits() Option {
+	return func(c *Contextualizer) {
+		c.DetectGitCommits = true
+	}
+}
+
+// WithEIP55 drops eth matches with an invalid mixed-case checksum.
+func WithEIP55() Option {
+	return func(c *Contextualizer) {
+		c.VerifyEIP55 = true
+	}
+}
+
+// WithJWTDecoding attaches decoded jwt headers and payloads to matches.
+func WithJWTDecoding() Option {
+	return func(c *Contextualizer) {
+		c.DecodeJWT = true
+	}
+}
+
+// WithURLDecomposition attaches the components of url matches and emits
+// their hosts (see Contextualizer.DecomposeURLs).
+func WithURLDecomposition() Option {
+	return func(c *Contextualizer) {
+		c.DecomposeURLs = true
+	}
+}
+
+// WithEnricher registers e for Enrich, with each call cancelled after
+// timeout unless it is zero.
+func WithEnricher(e Enricher, timeout time.Duration) Option {
+	return func(c *Contextualizer) {
+		c.enrichers = append(c.enrichers, enricher{Enricher: e, timeout: timeout})
+	}
+}
+
+// WithFilters appends to the filters applied to every match (see Filter).
+func WithFilters(f ...Filter) Option {
+	return func(c *Contextualizer) {
+		c.Filters = append(c.Filters, f...)
+	}
+}
+
+// WithPreprocessors appends to the preprocessors run over the input (see
+// Preprocessor).
+func WithPreprocessors(p ...Preprocessor) Option {
+	return func(c *Contextualizer) {
+		c.Preprocessors = append(c.Preprocessors, p...)
+	}
+}
+
+// WithHTMLStripping scans only the visible text and links of HTML input
+// (see Contextualizer.StripHTML).
+func WithHTMLStripping() Option {
+	return func(c *Contextualizer) {
+		c.StripHTML = true
+	}
+}
+
+// WithEscapeDecoding decodes percent escapes and HTML character references
+// before matching (see Contextualizer.DecodeEscapes).
+func WithEscapeDecoding() Option {
+	return func(c *Contextualizer) {
+		c.DecodeEscapes = true
+	}
+}
+
+// WithBase64Decoding enables the base64 pre-decoding pass.
+func WithBase64Decoding(cfg Base64Config) Option {
+	return func(c *Contextualizer) {
+		c.Base64 = &cfg
+	}
+}
+
+// WithEntropy enables the high-entropy secret candidate detector.
+func WithEntropy(cfg EntropyConfig) Option {
+	return func(c *Contextualizer) {
+		c.Entropy = &cfg
+	}
+}
+
+// WithPEMRedaction replaces the body of pem matches with a placeholder.
+func WithPEMRedaction() Option {
+	return func(c *Contextualizer) {
+		c.RedactPEM = true
+	}
+}
+
+// WithContextWindow stores n bytes of text on each side of every match in
+// Match.Context.
+func WithContextWindow(n int) Option {
+	return func(c *Contextualizer) {
+		c.ContextWindow = n
+	}
+}
+
+// WithSentenceContext stores the sentence containing every match in
+// Match.Context.
+func WithSentenceContext() Option {
+	return func(c *Contextualizer) {
+		c.SentenceContext = true
+	}
+}
+
+// WithLineNumbers fills Match.Line and Match.Column.
+func WithLineNumbers() Option {
+	return func(c *Contextualizer) {
+		c.LineNumbers = true
+	}
+}
+
+// WithConfidence fills Match.Confidence.
+func WithConfidence() Option {
+	return func(c *Contextualizer) {
+		c.ScoreMatches = true
+	}
+}
+
+// WithHashHeuristics scores matches and demotes hex identifiers posing as
+// hashes (see Contextualizer.HashHeuristics).
+func WithHashHeuristics() Option {
+	return func(c *Contextualizer) {
+		c.ScoreMatches = true
+		c.HashHeuristics = true
+	}
+}
+
+// WithKeywordHints adds hints for matches of kind (see KeywordHint).
+func WithKeywordHints(kind string, hints ...KeywordHint) Option {
+	return func(c *Contextualizer) {
+		if c.KeywordHints == nil {
+			c.KeywordHints = make(map[string][]KeywordHint)
+		}
+		c.KeywordHints[kind] = append(c.KeywordHints[kind], hints...)
+	}
+}
+
+// WithClassification assigns a severity and tags to matches of kind (see
+// Contextualizer.Classifications).
+func WithClassification(kind string, cl Classification) Option {
+	return func(c *Contextualizer) {
+		if c.Classifications == nil {
+			c.Classifications = make(map[string]Classification)
+		}
+		c.Classifications[kind] = cl
+	}
+}
+
+// WithKindTags adds tags to the Classification of kind.
+func WithKindTags(kind string, tags ...string) Option {
+	return func(c *Contextualizer) {
+		if c.Classifications == nil {
+			c.Classifications = make(map[string]Classification)
+		}
+		cl := c.Classifications[kind]
+		cl.Tags = appendUnique(cl.Tags, tags...)
+		c.Classifications[kind] = cl
+	}
+}
+
+// WithTLP marks every match with t (see Contextualizer.TLP).
+func WithTLP(t TLP) Option {
+	return func(c *Contextualizer) {
+		c.TLP = t
+	}
+}
+
+// WithTagger appends to the taggers labelling every match (see Tagger).
+func WithTagger(t ...Tagger) Option {
+	return func(c *Contextualizer) {
+		c.Taggers = append(c.Taggers, t...)
+	}
+}
+
+// WithSourceTags tags the matches extracted into Results with the given
+// Source (see Contextualizer.SourceTags).
+func WithSourceTags(source string, tags ...string) Option {
+	return func(c *Contextualizer) {
+		if c.SourceTags == nil {
+			c.SourceTags = make(map[string][]string)
+		}
+		c.SourceTags[source] = appendUnique(c.SourceTags[source], tags...)
+	}
+}
+
+// WithMarker sets how Annotate highlights matches, e.g. HTMLMarker().
+func WithMarker(m Marker) Option {
+	return func(c *Contextualizer) {
+		c.Marker = m
+	}
+}
+
+// WithFileExtensions replaces the extensions that make a domain-shaped
+// token a filename (see Contextualizer.FileExtensions). Leading dots are
+// ignored.
+func WithFileExtensions(exts ...string) Option {
+	return func(c *Contextualizer) {
+		c.FileExtensions = make([]string, 0, len(exts))
+		for _, ext := range exts {
+			c.FileExtensions = append(c.FileExtensions, strings.ToLower(strings.TrimPrefix(ext, ".")))
+		}
+	}
+}
+
+// WithURLSchemes replaces the url expression with one matching the given
+// schemes, such as "https", "file", "smb", "ftps", "ws", "wss", "tcp" or
+// "ldap". mailto: links are reported as email matches of their
+// recipients. See DefaultURLSchemes for the built-in set.
+func WithURLSchemes(schemes ...string) Option {
+	return func(c *Contextualizer) {
+		regex := urlExpression(schemes)
+		c.Expressions["url"] = regex
+		c.attachPrefilters(map[string]*regexp.Regexp{"url": regex})
+	}
+}
+
+// WithKindPriority sets the order in which kinds shadow each other (see
+// Contextualizer.KindPriority).
+func WithKindPriority(kinds ...string) Option {
+	return func(c *Contextualizer) {
+		c.KindPriority = kinds
+	}
+}
+
+// WithPreserveCase keeps the original casing of matches (see
+// Contextualizer.PreserveCase).
+func WithPreserveCase() Option {
+	return func(c *Contextualizer) {
+		c.PreserveCase = true
+	}
+}
+
+// WithNormalization fills Match.Normalized and deduplicates by it (see
+// Contextualizer.Normalize).
+func WithNormalization() Option {
+	return func(c *Contextualizer) {
+		c.Normalize = true
+	}
+}
+
+// WithNormalizer replaces the canonical form of kind with n and turns on
+// normalization.
+func WithNormalizer(kind string, n Normalizer) Option {
+	return func(c *Contextualizer) {
+		if c.Normalizers == nil {
+			c.Normalizers = make(map[string]Normalizer)
+		}
+		c.Normalizers[kind] = n
+		c.Normalize = true
+	}
+}
+
+// WithCanonicalURLs canonicalizes url matches (see
+// Contextualizer.CanonicalURLs).
+func WithCanonicalURLs() Option {
+	return func(c *Contextualizer) {
+		c.CanonicalURLs = true
+	}
+}
+
+// WithEquivalentURLs folds equivalent URLs into one match (see
+// Contextualizer.EquivalentURLs).
+func WithEquivalentURLs() Option {
+	return func(c *Contextualizer) {
+		c.EquivalentURLs = true
+	}
+}
+
+// WithPlusAddressingStripped correlates email addresses that differ only
+// in their "+tag" through Match.Normalized and turns on normalization.
+func WithPlusAddressingStripped() Option {
+	return func(c *Contextualizer) {
+		c.StripPlusAddressing = true
+		c.Normalize = true
+	}
+}
+
+// WithGmailDotsIgnored correlates Gmail addresses that differ only in the
+// dots of their local part through Match.Normalized and turns on
+// normalization.
+func WithGmailDotsIgnored() Option {
+	return func(c *Contextualizer) {
+		c.GmailDots = true
+		c.Normalize = true
+	}
+}
+
+// WithBatchWorkers scans up to n documents of an ExtractBatch at a time.
+func WithBatchWorkers(n int) Option {
+	return func(c *Contextualizer) {
+		c.BatchWorkers = n
+	}
+}
+
+// WithChunkSize sets the chunk size and overlap of ExtractReader and
+// ExtractFile (see Contextualizer.ChunkSize).
+func WithChunkSize(size, overlap int) Option {
+	return func(c *Contextualizer) {
+		c.ChunkSize, c.ChunkOverlap = size, overlap
+	}
+}
+
+// WithWorkers runs the per-kind scans of ExtractAll on n goroutines.
+func WithWorkers(n int) Option {
+	return func(c *Contextualizer) {
+		c.Workers = n
+	}
+}
+
+// WithLimits bounds the input size and match counts of every extraction.
+func WithLimits(l Limits) Option {
+	return func(c *Contextualizer) {
+		c.Limits = &l
+	}
+}
@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestNewContextualizer_Options(t *testing.T) {
+	c := NewContextualizer(
+		WithIgnorePrivateIPs(true),
+		WithIgnoredDomains(".Example.com"),
+		WithIgnoredEmails("Admin@Test.com"),
+		WithExpressions(map[string]*regexp.Regexp{"ticket": regexp.MustCompile(`\b(INC\d{6})\b`)}),
+		WithKinds("ipv4", "domain", "ticket"),
+		WithDefanged(),
+	)
+
+	if !c.Checks.IgnorePrivateIPs || !c.MatchDefanged {
+		t.Errorf("flags not applied: %+v", c)
+	}
+	if _, ok := c.Checks.IgnoredDomains["example.com"]; !ok {
+		t.Errorf("IgnoredDomains = %v", c.Checks.IgnoredDomains)
+	}
+	if _, ok := c.Checks.IgnoredEmails["admin@test.com"]; !ok {
+		t.Errorf("IgnoredEmails = %v", c.Checks.IgnoredEmails)
+	}
+	if len(c.Expressions) != 3 || c.Expressions["ticket"] == nil {
+		t.Errorf("Expressions = %v", c.Expressions)
+	}
+
+	results := c.ExtractAll("INC000123 from 10.0.0.1 and 8.8.8[.]8 via www.example.com")
+	if len(results["ticket"]) != 1 || len(results["ipv4"]) != 1 || len(results["domain"]) != 0 {
+		t.Errorf("ExtractAll() = %v", results)
+	}
+}
+
+func TestContextualizer_PreserveCase(t *testing.T) {
+	c := NewContextualizer(WithPreserveCase())
+	text := "Beacon EVIL.Com, mail Bad@Evil.ORG, MAC 02-AA-BB-CC-DD-EE"
+
+	domains := c.GetMatches(text, "domain", c.Expressions["domain"])
+	if len(domains) != 2 || domains[0].Value != "EVIL.Com" || domains[0].Normalized != "evil.com" {
+		t.Errorf("domain = %+v", domains)
+	}
+	got := c.ExtractAll(text)
+	if m := got["email"]; len(m) != 1 || m[0].Value != "Bad@Evil.ORG" || m[0].Normalized != "bad@evil.org" {
+		t.Errorf("email = %+v", m)
+	}
+	if m := got["mac"]; len(m) != 1 || m[0].Value != "02-AA-BB-CC-DD-EE" || m[0].Normalized != "02:aa:bb:cc:dd:ee" {
+		t.Errorf("mac = %+v", m)
+	}
+
+	c.PreserveCase = false
+	if m := c.GetMatches(text, "domain", c.Expressions["domain"]); m[0].Value != "evil.com" || m[0].Normalized != "" {
+		t.Errorf("domain without PreserveCase = %+v", m)
+	}
+}
@@ -0,0 +1,152 @@
+package parser
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+)
+
+// DefaultKindPriority is the KindPriority used when none is set: a match
+// inside the span of a match of an earlier kind is dropped, so an email
+// address no longer also yields its domain.
+var DefaultKindPriority = []string{"url", "email", "domain", "filepath", "winpath", "unc", "filename"}
+
+// pathKinds shadow the kinds missing from the priority list as URLs do,
+// so the hash in C:\Temp\<md5>.exe is only reported as part of the path.
+// filepath is left out since schemeless URLs such as
+// github.com/org/repo/commit/<sha1> also match it.
+var pathKinds = []string{"winpath", "unc"}
+
+// wrappers maps kinds that report the address inside them as a child
+// match to the kinds of that address, whose plain matches they shadow, so
+// "8.8.8.8:53" yields 8.8.8.8 and `\\fs01.corp.example\share` yields
+// fs01.corp.example once.
+var wrappers = map[string][]string{
+	"ipport": {"ipv4", "ipv6"},
+	"unc":    {"domain", "hostname", "ipv4", "ipv6"},
+}
+
+type span struct{ start, end int }
+
+// claimSet holds the claimed spans of one kind sorted by start, so the
+// spans that may contain a match are found by binary search.
+type claimSet struct {
+	kind  string
+	rank  int
+	spans []span
+	// maxLen is the length of the longest span, which bounds how far
+	// before a match a span containing it can start.
+	maxLen int
+}
+
+func (s *claimSet) add(sp span) {
+	i := sort.Search(len(s.spans), func(i int) bool { return s.spans[i].start > sp.start })
+	s.spans = slices.Insert(s.spans, i, sp)
+	s.maxLen = max(s.maxLen, sp.end-sp.start)
+}
+
+// covers reports whether a span of s contains [start, end).
+func (s *claimSet) covers(start, end int) bool {
+	i := sort.Search(len(s.spans), func(i int) bool { return s.spans[i].start > start })
+	for i--; i >= 0 && s.spans[i].start >= end-s.maxLen; i-- {
+		if s.spans[i].end >= end {
+			return true
+		}
+	}
+	return false
+}
+
+// resolver drops matches that overlap a match of a higher priority kind.
+// Kinds missing from the priority list never shadow others, except for
+// wrappers, and are only shadowed by URLs, paths and wrappers. Profile kinds such
+// as tokens and webhooks are looked for inside other matches on purpose
+// and are never shadowed.
+type resolver struct {
+	rank    map[string]int
+	claimed []*claimSet
+	// mailto holds the dedup keys of the email addresses already emitted
+	// from mailto: links, so the email scan doesn't emit them again.
+	mailto map[string]bool
+}
+
+func (c *Contextualizer) newResolver() *resolver {
+	priority := c.KindPriority
+	if priority == nil {
+		priority = DefaultKindPriority
+	}
+	r := &resolver{rank: make(map[string]int, len(priority))}
+	for i, kind := range priority {
+		if _, dup := r.rank[kind]; !dup {
+			r.rank[kind] = i
+		}
+	}
+	return r
+}
+
+// order returns the kinds of exprs other than url in scan order, starting
+// with the ones that shadow what comes after them: wrappers, which shadow
+// the addresses inside them whatever their rank, and ranked kinds by
+// priority. The rest follow sorted by name.
+func (r *resolver) order(exprs map[string]*regexp.Regexp) (ranked, rest []string) {
+	var wrapping []string
+	for kind := range exprs {
+		if kind == "url" {
+			continue
+		}
+		if _, ok := wrappers[kind]; ok {
+			wrapping = append(wrapping, kind)
+		} else if _, ok := r.rank[kind]; ok {
+			ranked = append(ranked, kind)
+		} else {
+			rest = append(rest, kind)
+		}
+	}
+	sort.Strings(wrapping)
+	sort.Slice(ranked, func(i, j int) bool { return r.rank[ranked[i]] < r.rank[ranked[j]] })
+	sort.Strings(rest)
+	return append(wrapping, ranked...), rest
+}
+
+// shadowed reports whether a match of kind at [start, end) lies within a
+// claimed span that takes precedence.
+func (r *resolver) shadowed(kind string, start, end int) bool {
+	if isProfileKind(kind) {
+		return false
+	}
+	rank, ranked := r.rank[kind]
+	for _, set := range r.claimed {
+		if set.kind == kind {
+			continue
+		}
+		if (ranked && set.rank < rank || !ranked && shadowsUnranked(set.kind) || slices.Contains(wrappers[set.kind], kind)) && set.covers(start, end) {
+			return true
+		}
+	}
+	return false
+}
+
+func shadowsUnranked(kind string) bool {
+	return kind == "url" || slices.Contains(pathKinds, kind)
+}
+
+// claim records an accepted match of kind at [start, end). Only ranked
+// kinds, URLs and wrappers shadow other matches, so other kinds are not
+// recorded.
+func (r *resolver) claim(kind string, start, end int) {
+	rank, ranked := r.rank[kind]
+	if _, wraps := wrappers[kind]; !ranked && !wraps && kind != "url" {
+		return
+	}
+	if !ranked {
+		rank = len(r.rank)
+	}
+	for _, set := range r.claimed {
+		if set.kind == kind {
+			set.add(span{start, end})
+			return
+		}
+	}
+	set := &claimSet{kind: kind, rank: rank}
+	set.add(span{start, end})
+	r.claimed = append(r.claimed, set)
+}
@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestContextualizer_OverlapResolution(t *testing.T) {
+	text := "mail bad@evil.org, C2 http://1.2.3.4/gate twice http://1.2.3.4/gate, also evil.org and notes.txt"
+
+	for _, workers := range []int{0, 4} {
+		c := NewContextualizer(WithWorkers(workers))
+		got := c.ExtractAll(text)
+
+		if len(got["email"]) != 1 {
+			t.Errorf("workers=%d: email = %v", workers, got["email"])
+		}
+		// evil.org is only reported for its standalone occurrence, and
+		// notes.txt is a filename.
+		if d := got["domain"]; len(d) != 1 || d[0].Value != "evil.org" || d[0].Start != 74 {
+			t.Errorf("workers=%d: domain = %v", workers, d)
+		}
+		if got["ipv4"] != nil {
+			t.Errorf("workers=%d: ipv4 inside URLs = %v", workers, got["ipv4"])
+		}
+	}
+}
+
+func TestContextualizer_PathOverlap(t *testing.T) {
+	text := `dropped C:\Windows\Temp\d41d8cd98f00b204e9800998ecf8427e.exe, staged on \\fs01.evil.net\share$\run.ps1 and \\10.1.2.3\c$\tools\invoice.pdf.js`
+
+	for _, workers := range []int{0, 4} {
+		c := NewContextualizer(WithWorkers(workers))
+		got := c.ExtractAll(text)
+
+		if len(got["winpath"]) != 1 || len(got["unc"]) != 2 {
+			t.Errorf("workers=%d: winpath = %v, unc = %v", workers, got["winpath"], got["unc"])
+		}
+		if got["filename"] != nil || got["md5"] != nil {
+			t.Errorf("workers=%d: filename = %v, md5 = %v inside paths", workers, got["filename"], got["md5"])
+		}
+		for _, kind := range []string{"domain", "ipv4"} {
+			if m := got[kind]; len(m) != 1 || m[0].Parent == "" {
+				t.Errorf("workers=%d: %s = %v, want the host of the UNC path", workers, kind, m)
+			}
+		}
+	}
+}
+
+func TestContextualizer_KindPriority(t *testing.T) {
+	text := "mail bad@evil.org"
+
+	// Only kinds in the list shadow each other.
+	c := NewContextualizer(WithKindPriority("url", "domain"))
+	got := c.ExtractAll(text)
+	if len(got["email"]) != 1 || len(got["domain"]) != 1 {
+		t.Errorf("ExtractAll() = %v", got)
+	}
+
+	c.KindPriority = nil
+	got = c.ExtractAll(text)
+	if len(got["email"]) != 1 || got["domain"] != nil {
+		t.Errorf("default priority ExtractAll() = %v", got)
+	}
+}
+
+func TestResolver_Order(t *testing.T) {
+	c := NewContextualizer()
+	ranked, rest := c.newResolver().order(c.expressions())
+	if want := []string{"ipport", "unc", "email", "domain", "filepath", "winpath", "filename"}; !reflect.DeepEqual(ranked, want) {
+		t.Errorf("ranked = %v, want %v", ranked, want)
+	}
+	for _, kind := range rest {
+		if kind == "url" || kind == "email" {
+			t.Errorf("rest contains %s", kind)
+		}
+	}
+}
+
+// BenchmarkExtractAllManyDomains checks that overlap resolution stays
+// roughly linear in the number of matches.
+func BenchmarkExtractAllManyDomains(b *testing.B) {
+	var sb strings.Builder
+	for i := range 80000 {
+		fmt.Fprintf(&sb, "host%d.example.com ", i)
+	}
+	text := sb.String()
+	c := NewContextualizer()
+	b.ReportAllocs()
+	for b.Loop() {
+		c.ExtractAll(text)
+	}
+}
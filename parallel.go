@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"regexp"
+	"sync"
+)
+
+// extractParallel is extract with the per-kind scans spread over c.Workers
+// goroutines. URLs, the kinds in the priority list and wrappers are still
+// scanned first, since every other kind needs their spans, and results are
+// merged in sorted kind order so the output does not depend on scheduling.
+// Limits are applied during the merge.
+func (c *Contextualizer) extractParallel(text string, exprs map[string]*regexp.Regexp, entropy *EntropyConfig, flags ScanFlag) map[string][]Match {
+	lim := c.newLimiter()
+	src := c.newSource(lim.clip(text), flags)
+	results := make(map[string][]Match)
+	var found []Match
+	var encoded map[string]bool
+	if c.Base64 != nil && !src.noDedup {
+		encoded = make(map[string]bool)
+	}
+	collect := func(m Match) bool {
+		if encoded != nil {
+			encoded[c.encodedKey(m)] = true
+		}
+		m, keep := c.filter(m)
+		if !keep {
+			return true
+		}
+		keep, more := lim.admit(m)
+		if keep {
+			results[m.Type] = append(results[m.Type], m)
+			found = append(found, m)
+		}
+		return more
+	}
+
+	// Ranked kinds and wrappers shadow the ones after them, so only the
+	// rest can run concurrently.
+	res := c.newResolver()
+	if !c.scanURLs(src, exprs, res, collect) {
+		return results
+	}
+	ranked, kinds := res.order(exprs)
+	for _, kind := range ranked {
+		if !c.scanKind(src, kind, exprs[kind], res, collect) {
+			return results
+		}
+	}
+
+	perKind := make([][]Match, len(kinds))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(c.Workers, len(kinds)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				c.scanKind(src, kinds[i], exprs[kinds[i]], res, func(m Match) bool {
+					perKind[i] = append(perKind[i], m)
+					return true
+				})
+			}
+		}()
+	}
+	for i := range kinds {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, matches := range perKind {
+		for _, m := range matches {
+			if !collect(m) {
+				return results
+			}
+		}
+	}
+	if !c.scanEntropy(src, entropy, found, collect) {
+		return results
+	}
+	if c.Base64 != nil {
+		c.scanBase64(src, exprs, encoded, collect)
+	}
+	return results
+}
@@ -0,0 +1,37 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContextualizer_ParallelExtraction(t *testing.T) {
+	text := "Hash d41d8cd98f00b204e9800998ecf8427e from 8.8.8.8:443 via http://evil.com/x, " +
+		"mail bad@evil.org, host sub.test.org, mac 00:1a:2b:3c:4d:5e, path C:\\Temp\\x.exe"
+
+	serial := NewContextualizer().ExtractAll(text)
+	parallel := NewContextualizer(WithWorkers(4))
+
+	first := parallel.ExtractAll(text)
+	for i := 0; i < 5; i++ {
+		got := parallel.ExtractAll(text)
+		if !reflect.DeepEqual(got, first) {
+			t.Fatalf("parallel results differ between runs: %v vs %v", got, first)
+		}
+		if len(got) != len(serial) {
+			t.Fatalf("parallel kinds = %d, serial kinds = %d", len(got), len(serial))
+		}
+		for kind, matches := range serial {
+			if kind == "base_domain" || kind == "ipv4" {
+				// Derived matches may be interleaved differently.
+				if len(got[kind]) != len(matches) {
+					t.Errorf("%s: %v, want %v", kind, got[kind], matches)
+				}
+				continue
+			}
+			if !reflect.DeepEqual(got[kind], matches) {
+				t.Errorf("%s: %v, want %v", kind, got[kind], matches)
+			}
+		}
+	}
+}
@@ -1,114 +1,376 @@
 package parser
 
 import (
+	"encoding/pem"
+	"fmt"
+	"maps"
 	"net"
+	"net/netip"
 	"net/url"
+	"os"
 	"regexp"
+	"strconv"
 	"strings"
-
-	"golang.org/x/net/publicsuffix"
+	"sync"
+	"unicode"
 )
 
 type Contextualizer struct {
-	ID          string
+	ID string
+	// Expressions maps kinds to their expressions. Modify it directly only
+	// before the Contextualizer is shared; afterwards use AddExpression,
+	// SetExpression and RemoveExpression.
 	Expressions map[string]*regexp.Regexp
 	Checks      *PrivateChecks
+	mu          sync.RWMutex // guards Expressions, Checks, disabled, nationalIDs, extractors, validators, enrichers, prefilters, added, exprChanges, kindChanges and the fields set by Reload and ApplyConfig
+	disabled    map[string]struct{}
+	nationalIDs map[string]NationalID
+	extractors  map[string]Extractor
+	validators  map[string][]Validator
+	enrichers   []enricher
+	prefilters  map[*regexp.Regexp]prefilter
+	onlyKinds   map[string]struct{} // set by WithKinds during construction
+	// MatchDefanged makes extraction recognize defanged indicators such as
+	// hxxp://evil[.]com or 1.2.3[.]4. Matches are reported refanged and the
+	// text as it appeared in the input is kept in Match.Raw.
+	MatchDefanged bool
+	// DefangOutput defangs every emitted Value (see Defang) so results can
+	// be published without producing clickable links.
+	DefangOutput bool
+	// VerifyEIP55 drops eth matches whose mixed-case EIP-55 checksum does
+	// not verify. Single-case addresses carry no checksum and are kept.
+	VerifyEIP55 bool
+	// RequireKnownTLD drops domain matches that don't end in an ICANN
+	// public suffix (see KnownTLD), such as "config.yaml" or "notes.txt".
+	// Filenames whose extension is a real ccTLD, like "setup.sh", remain.
+	RequireKnownTLD bool
+	// DetectGitCommits reports sha1 matches that read as git commits, next
+	// to "commit", a repository URL path or a ref, as kind "git_commit".
+	DetectGitCommits bool
+	// DecodeJWT stores the decoded header and payload of jwt matches in
+	// Match.Metadata.
+	DecodeJWT bool
+	// DecomposeURLs attaches the components of url matches to
+	// Match.Metadata and emits their host as a child match.
+	DecomposeURLs bool
+	// Entropy enables the generic high-entropy secret detector in
+	// ExtractAll when non-nil.
+	Entropy *EntropyConfig
+	// RedactPEM replaces the base64 body of pem matches with a placeholder
+	// so key material never leaves the parser. The fingerprint in
+	// Match.Metadata still identifies the block.
+	RedactPEM bool
+	// ContextWindow, when positive, stores that many bytes of surrounding
+	// text on each side of a match in Match.Context.
+	ContextWindow int
+	// SentenceContext stores the sentence containing a match in
+	// Match.Context instead of a fixed window.
+	SentenceContext bool
+	// LineNumbers fills Match.Line and Match.Column. It is off by default
+	// because it needs an extra pass over the input.
+	LineNumbers bool
+	// ScoreMatches fills Match.Confidence.
+	ScoreMatches bool
+	// HashHeuristics lowers the Confidence of md5, sha1, sha256 and sha512
+	// matches that look like hex identifiers from logs rather than
+	// digests, see hashNoise.
+	HashHeuristics bool
+	// Workers, when greater than one, makes ExtractAll and ExtractKinds run
+	// the per-kind scans on that many goroutines.
+	Workers int
+	// DisablePrefilter always runs every expression, skipping the cheap
+	// literal checks that normally rule out expressions that cannot match.
+	DisablePrefilter bool
+	// KindPriority orders kinds for overlap resolution: a match inside the
+	// span of a match of an earlier kind is dropped. Nil means
+	// DefaultKindPriority.
+	KindPriority []string
+	// FileExtensions are the lowercase extensions, without the dot, that
+	// make a domain-shaped token such as "update.zip" a filename rather
+	// than a domain, unless a scheme, '@', path or port around it says
+	// otherwise. Nil means DefaultFileExtensions; an empty slice turns the
+	// heuristic off.
+	FileExtensions []string
+	// PreserveCase keeps matches as they were written in Match.Value and
+	// stores the canonical, lowercased form in Match.Normalized.
+	PreserveCase bool
+	// Normalize fills Match.Normalized with the canonical form of every
+	// match (see Normalize) and deduplicates matches by it, so an
+	// indicator written several ways is reported once.
+	Normalize bool
+	// Normalizers override the canonical form of the kinds they name.
+	Normalizers map[string]Normalizer
+	// KeywordHints boost or re-type matches of a kind that follow one of
+	// the hint's keywords, see KeywordHint. The first matching hint of a
+	// kind applies.
+	KeywordHints map[string][]KeywordHint
+	// Classifications assign a severity and tags to kinds, which every
+	// returned match of the kind carries. They are applied before Filters.
+	Classifications map[string]Classification
+	// TLP marks every returned match, unless the Result of an extraction
+	// sets its own (see Result.TLP).
+	TLP TLP
+	// Taggers add labels to every returned match, after Classifications
+	// and before Filters.
+	Taggers []Tagger
+	// SourceTags are added to the matches extracted into a Result whose
+	// Source they are keyed by, e.g. {"gateway": {"email"}}.
+	SourceTags map[string][]string
+	// Marker highlights matches in Annotate. The zero Marker means
+	// ANSIMarker.
+	Marker Marker
+	// StripPlusAddressing drops the "+tag" of an email's local part from
+	// its canonical form, and GmailDots drops the dots from Gmail local
+	// parts, which Gmail ignores.
+	StripPlusAddressing bool
+	GmailDots           bool
+	// EnrichWorkers bounds the concurrent enricher calls of Enrich.
+	// Defaults to 8.
+	EnrichWorkers int
+	// BatchWorkers bounds the documents ExtractBatch scans at a time; it
+	// defaults to GOMAXPROCS.
+	BatchWorkers int
+	// ChunkSize and ChunkOverlap bound the bytes ExtractReader and
+	// ExtractFile hold at a time; ChunkSize defaults to 4 MiB with a 4 KiB
+	// overlap. The overlap is capped at half the chunk.
+	ChunkSize    int
+	ChunkOverlap int
+	// Filters are applied to every match before it is returned (see
+	// Filter).
+	Filters []Filter
+	// Preprocessors rewrite the input before it is scanned (see
+	// Preprocessor).
+	Preprocessors []Preprocessor
+	// StripHTML treats the input as HTML: only the visible text and the
+	// href, src and action attribute values are scanned, so scripts,
+	// styles and markup don't produce matches. Offsets still refer to the
+	// input as written.
+	StripHTML bool
+	// DecodeEscapes decodes percent escapes and HTML character references
+	// before matching, so indicators in encoded query strings and HTML
+	// exports are found. Offsets still refer to the input as written.
+	DecodeEscapes bool
+	// Base64, when non-nil, also extracts indicators from the content of
+	// base64 blobs in the input.
+	Base64 *Base64Config
+	// CanonicalURLs rewrites url matches with CanonicalURL, so links that
+	// differ only in tracking parameters, parameter order, default ports
+	// or escaping are reported once.
+	CanonicalURLs bool
+	// EquivalentURLs reports the http and https, default-port and
+	// trailing-slash variants of a URL as one match, listing the forms
+	// that were folded into it in Match.Aliases.
+	EquivalentURLs bool
+	// Limits, when non-nil, bounds the input size and the number of
+	// matches of every extraction.
+	Limits *Limits
+
+	// configPath and configOpts are recorded by LoadConfig for Reload,
+	// configInfo describes the file as it was last loaded.
+	configPath string
+	configOpts []Option
+	configInfo os.FileInfo
+	// added collects the entries of the Load* and AddIgnorePattern calls,
+	// which Reload merges into the checks of the file.
+	added *PrivateChecks
+	// exprChanges and kindChanges record the expressions set, or removed
+	// when nil, and the kinds disabled, or enabled when false, since c was
+	// loaded, which Reload applies on top of the file.
+	exprChanges map[string]*regexp.Regexp
+	kindChanges map[string]bool
 }
 
 type PrivateChecks struct {
+	// IgnorePrivateIPs drops private, loopback and link-local addresses,
+	// IPv4 and IPv6 alike, from ipv4, ipv6 and ipport matches.
 	IgnorePrivateIPs bool
-	IgnoredDomains   map[string]struct{}
-	IgnoredEmails    map[string]struct{}
+	// IgnoreBogons drops addresses in reserved ranges that should never
+	// appear on the public internet, such as 0.0.0.0/8, 100.64.0.0/10 and
+	// the documentation prefixes, see bogonPrefixes.
+	IgnoreBogons   bool
+	IgnoredDomains map[string]struct{}
+	IgnoredEmails  map[string]struct{}
+	// IgnoredIPs drops these addresses from ipv4, ipv6 and ipport matches.
+	IgnoredIPs map[netip.Addr]struct{}
+	// IgnoredCIDRs drops addresses within these ranges, such as an
+	// organization's public ranges, from ipv4, ipv6 and ipport matches, and
+	// ranges within them from cidr matches.
+	IgnoredCIDRs []netip.Prefix
+	// IgnoredPatterns drops matches of a kind whose value matches any of
+	// the kind's expressions.
+	IgnoredPatterns map[string][]*regexp.Regexp
+	// IgnoredHashes drops md5, sha1, sha256 and sha512 matches of these
+	// lowercase digests, such as hashes of known-good binaries.
+	IgnoredHashes map[string]struct{}
+	// HashAllowlist, when non-nil, is consulted for hashes not in
+	// IgnoredHashes.
+	HashAllowlist HashAllowlist
+	// TopDomains drops popular domains and their hosts, see
+	// LoadTopDomains.
+	TopDomains map[string]struct{}
+	// IgnoreLocalMACs drops locally administered and multicast MAC addresses.
+	IgnoreLocalMACs bool
 }
 
-type Match struct {
-	Value string
-	Type  string
+func (p *PrivateChecks) ignoreDomains(domains ...string) {
+	for _, d := range domains {
+		p.IgnoredDomains[asciiDomain(strings.TrimPrefix(d, "."))] = struct{}{}
+	}
 }
 
-func NewContextualizer(ignoreIPs bool, ignoreDomains []string, ignoreEmails []string) *Contextualizer {
-	domainMap := make(map[string]struct{}, len(ignoreDomains))
-	for _, d := range ignoreDomains {
-		domainMap[strings.ToLower(strings.TrimPrefix(d, "."))] = struct{}{}
+func (p *PrivateChecks) ignoreEmails(emails ...string) {
+	for _, e := range emails {
+		p.IgnoredEmails[asciiEmail(e)] = struct{}{}
 	}
+}
 
-	emailMap := make(map[string]struct{}, len(ignoreEmails))
-	for _, e := range ignoreEmails {
-		emailMap[strings.ToLower(e)] = struct{}{}
-	}
+type Match struct {
+	Value string
+	Type  string
+	// Raw is the original input text when it differs from Value because
+	// the indicator was defanged or escaped. It is empty otherwise.
+	Raw string
+	// Start and End are the byte offsets of the match in the original
+	// input, so text[Start:End] is the indicator as it was written.
+	Start int
+	End   int
+	// Line and Column locate Start as a 1-based line number and rune
+	// column when LineNumbers is set; both are 0 otherwise.
+	Line   int
+	Column int
+	// Confidence is a score between 0 and 1 reflecting how likely the
+	// match is a real indicator, filled when ScoreMatches is set.
+	Confidence float64
+	// Context is the text surrounding the match when ContextWindow or
+	// SentenceContext is set.
+	Context string
+	// Parent is the Value of the match this one was derived from, such as
+	// the domain behind a base_domain or the UNC path behind its host.
+	Parent string
+	// Metadata carries kind-specific details, such as the decoded header
+	// and payload of a jwt match.
+	Metadata map[string]string
+	// Normalized is the canonical form of Value, lowercased where case
+	// does not matter, when PreserveCase or Normalize is set. With
+	// PreserveCase, Value holds the text as it was written.
+	Normalized string
+	// Provenance is "encoded" for matches found in decoded base64 (see
+	// Contextualizer.Base64), whose Start and End then span the blob. It
+	// is empty for matches found in the text itself.
+	Provenance string
+	// Severity and Tags are copied from the Classification of the match's
+	// type, if any (see Contextualizer.Classifications). Tags also holds
+	// the labels of Contextualizer.Taggers and SourceTags.
+	Severity string
+	Tags     []string
+	// TLP is the sharing restriction of the match, from Contextualizer.TLP
+	// or the Result it was extracted into.
+	TLP TLP
+	// Aliases are the other forms of an equivalent URL reported as this
+	// match when EquivalentURLs is set, in the order they were found.
+	Aliases []string
+	// Sources are the Source of every Result the match was found in, when
+	// Results are combined with Result.Merge.
+	Sources []string
+}
 
-	return &Contextualizer{
+// NewContextualizer returns a Contextualizer with the built-in expressions,
+// configured by opts.
+func NewContextualizer(opts ...Option) *Contextualizer {
+	c := &Contextualizer{
 		ID: "contextualizer",
 		Checks: &PrivateChecks{
-			IgnorePrivateIPs: ignoreIPs,
-			IgnoredDomains:   domainMap,
-			IgnoredEmails:    emailMap,
+			IgnoredDomains: make(map[string]struct{}),
+			IgnoredEmails:  make(map[string]struct{}),
 		},
 		Expressions: map[string]*regexp.Regexp{
 			"md5":      regexp.MustCompile(`(?i)\b([a-f\d]{32})\b`),
 			"sha1":     regexp.MustCompile(`(?i)\b([a-f\d]{40})\b`),
 			"sha256":   regexp.MustCompile(`(?i)\b([a-f\d]{64})\b`),
 			"sha512":   regexp.MustCompile(`(?i)\b([a-f\d]{128})\b`),
-			"ipv4":     regexp.MustCompile(`(\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3})`),
-			"ipv6":     regexp.MustCompile(`(?i)([a-f\d]{4}(:[a-f\d]{4}){7})`),
-			"email":    regexp.MustCompile(`(?i)([a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,})`),
+			"ipv4":     regexp.MustCompile(`\b(\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3})\b`),
+			"ipv6":     regexp.MustCompile(`(?i)(?:^|[^\w:.%])((?:[a-f\d]{0,4}:){2,7}(?:\d{1,3}(?:\.\d{1,3}){3}|[a-f\d]{1,4})?(?:%[\w.-]+)?)`),
+			"ipport":   regexp.MustCompile(`(?i)((?:\b\d{1,3}(?:\.\d{1,3}){3}|\[[a-f\d:.]+(?:%[\w.-]+)?\]):\d{1,5})\b`),
+			"ssh_key":  regexp.MustCompile(`((?:ssh-(?:rsa|dss|ed25519)|ecdsa-sha2-nistp(?:256|384|521)|sk-ssh-ed25519@openssh\.com|sk-ecdsa-sha2-nistp256@openssh\.com) AAAA[A-Za-z0-9+/]+={0,3}(?: [^\s][^\r\n]*)?)`),
+			"jwt":      regexp.MustCompile(`\b(eyJ[\w-]{5,}\.[\w-]{5,}\.[\w-]*)`),
+			"email":    regexp.MustCompile(`(?i)([\p{L}\p{M}\d._%+-]+@[\p{L}\p{M}\d.-]+\.(?:xn--[a-z\d-]{2,59}|[a-z]{2,}|\p{L}{2,}))(?:[^\p{L}\p{M}\p{N}_]|$)`),
 			"url":      regexp.MustCompile(`(?i)((https?|ftp):\/\/[^\s/$.?#].[^\s]*)`),
-			"domain":   regexp.MustCompile(`(?i)([a-z0-9.-]+\.[a-z]{2,24})\b`),
-			"filepath": regexp.MustCompile(`([a-zA-Z0-9.-]+\/[a-zA-Z0-9.-]+)`),
-			"filename": regexp.MustCompile(`^[\w\-.]+\.[a-zA-Z]{2,4}$`),
+			"domain":   regexp.MustCompile(`(?i)([\p{L}\p{M}\d.-]+\.(?:xn--[a-z\d-]{2,59}|[a-z]{2,24}|\p{L}{2,24}))(?:[^\p{L}\p{M}\p{N}_]|$)`),
+			"filepath": regexp.MustCompile(`"((?:~|\.{1,2})?/[^"\n/]+(?:/[^"\n/]+)+)"|'((?:~|\.{1,2})?/[^'\n/]+(?:/[^'\n/]+)+)'|(?:^|[^\w.:/~\\-])((?:(?:~|\.{1,2})/|/[\w.@+-]+/|[\w.@+-]+/)(?:[\w.@+-]+/)*[\w.@+-]*[\w@+-])`),
+			"filename": regexp.MustCompile(`\b([\w-]+(?:\.[\w-]+)*\.[a-zA-Z][a-zA-Z\d]{1,4})\b`),
+			"winpath":  regexp.MustCompile(`(?i)((?:\b[a-z]:|%[a-z_][a-z\d_]*%)(?:\\{1,2}[^\\/:*?"<>|\s]*[^\\/:*?"<>|\s.,;])+)`),
+			"unc":      regexp.MustCompile(`(?i)(\\\\[a-z\d][a-z\d.-]*(?:\\[^\\/:*?"<>|\s]*[^\\/:*?"<>|\s.,;])+)`),
+			"ja4":      regexp.MustCompile(`\b([tqd]\d{2}[di]\d{4}[a-z\d]{2}_[a-f\d]{12}_[a-f\d]{12})\b`),
+			"jarm":     regexp.MustCompile(`(?i)\b([a-f\d]{62})\b`),
+			"mac":      regexp.MustCompile(`(?i)\b((?:[a-f\d]{2}:){5}[a-f\d]{2}|(?:[a-f\d]{2}-){5}[a-f\d]{2}|(?:[a-f\d]{4}\.){2}[a-f\d]{4})\b`),
+			"eth":      regexp.MustCompile(`\b(0x[a-fA-F\d]{40})\b`),
+			"btc":      regexp.MustCompile(`\b([13][a-km-zA-HJ-NP-Z1-9]{25,34}|bc1[ac-hj-np-z02-9]{11,71}|BC1[AC-HJ-NP-Z02-9]{11,71})\b`),
 		},
 	}
+
+	c.attachPrefilters(c.Expressions)
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.onlyKinds != nil {
+		for kind := range c.Expressions {
+			if _, ok := c.onlyKinds[kind]; !ok {
+				delete(c.Expressions, kind)
+			}
+		}
+		c.onlyKinds = nil
+	}
+	return c
+}
+
+// NewContextualizerWithIgnores is the original positional constructor.
+//
+// Deprecated: use NewContextualizer with WithIgnorePrivateIPs,
+// WithIgnoredDomains and WithIgnoredEmails.
+func NewContextualizerWithIgnores(ignoreIPs bool, ignoreDomains []string, ignoreEmails []string) *Contextualizer {
+	return NewContextualizer(
+		WithIgnorePrivateIPs(ignoreIPs),
+		WithIgnoredDomains(ignoreDomains...),
+		WithIgnoredEmails(ignoreEmails...),
+	)
 }
 
 func (c *Contextualizer) GetMatches(text string, kind string, regex *regexp.Regexp) []Match {
-	matches := regex.FindAllString(text, -1)
+	src := c.newSource(text, 0)
+	if !c.mayMatch(regex, src.text) {
+		return nil
+	}
+	matches := findAll(regex, src.text)
 	var results []Match
-	seen := make(map[string]bool)
+	seen := getSeen()
+	defer putSeen(seen)
+	var at map[string]int
+	if kind == "url" && c.EquivalentURLs {
+		at = make(map[string]int)
+	}
 
-	for _, match := range matches {
+	for _, idx := range matches {
+		match := src.text[idx[0]:idx[1]]
 		if kind == "url" {
-			match = strings.TrimRight(match, "/.,;:")
-			match = strings.TrimSuffix(match, "/")
+			match = trimURL(match)
+		}
+		end := idx[0] + len(match)
+		match = canonicalize(kind, match)
+		if kind == "url" && c.CanonicalURLs {
+			match = CanonicalURL(match)
 		}
 
 		cleanMatch := strings.ToLower(match)
-		if seen[cleanMatch] {
+		key := c.dedupKey(kind, match, cleanMatch)
+		if seen[key] {
+			if i, ok := at[key]; ok {
+				results[i] = addAlias(results[i], match)
+			}
 			continue
 		}
 
-		switch kind {
-		case "url":
-			if u, err := url.Parse(cleanMatch); err == nil {
-				if c.isDomainIgnored(u.Hostname()) {
-					continue
-				}
-			}
-		case "filepath":
-			if strings.HasPrefix(cleanMatch, "http") || strings.HasPrefix(cleanMatch, "www") || strings.HasPrefix(cleanMatch, "ftp") {
-				continue
-			}
-		case "ipv4":
-			if c.Checks.IgnorePrivateIPs && isPrivateIP(match) {
-				continue
-			}
-		case "email":
-			if _, exists := c.Checks.IgnoredEmails[cleanMatch]; exists {
-				continue
-			}
-			parts := strings.Split(cleanMatch, "@")
-			if len(parts) == 2 && c.isDomainIgnored(parts[1]) {
-				continue
-			}
-		case "domain":
-			if c.isDomainIgnored(cleanMatch) {
-				continue
-			}
-			baseDomain, err := extractSecondLevelDomain(cleanMatch)
-			if err == nil && baseDomain != "" && baseDomain != cleanMatch {
-				if !c.isDomainIgnored(baseDomain) {
-					results = append(results, Match{Value: baseDomain, Type: "base_domain"})
-				}
-			}
+		if !c.allowed(src.checks, kind, match, cleanMatch) {
+			continue
 		}
 
 		finalValue := match
@@ -117,106 +379,668 @@ func (c *Contextualizer) GetMatches(text string, kind string, regex *regexp.Rege
 		}
 
 		if finalValue != "" {
-			results = append(results, Match{Value: finalValue, Type: kind})
-			seen[cleanMatch] = true
+			m, children := c.newMatch(src, kind, finalValue, idx[0], end)
+			results = c.appendFiltered(results, children...)
+			n := len(results)
+			results = c.appendFiltered(results, m)
+			if at != nil && len(results) > n {
+				at[key] = n
+			}
+			seen[key] = true
 		}
 	}
 	return results
 }
 
 func (c *Contextualizer) ExtractAll(text string) map[string][]Match {
+	return c.extract(text, c.expressions(), c.Entropy, 0)
+}
+
+// ExtractKinds is like ExtractAll but only runs the expressions of the
+// given kinds. URLs only shadow other matches when "url" is among them, and
+// "secret_candidate" selects the entropy detector when it is configured.
+func (c *Contextualizer) ExtractKinds(text string, kinds ...string) (map[string][]Match, error) {
+	all := c.expressions()
+	exprs := make(map[string]*regexp.Regexp, len(kinds))
+	var entropy *EntropyConfig
+	for _, kind := range kinds {
+		if kind == "secret_candidate" && c.Entropy != nil {
+			entropy = c.Entropy
+			continue
+		}
+		regex, ok := all[kind]
+		if !ok && c.registered(kind) {
+			return nil, fmt.Errorf("kind %q is disabled", kind)
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown kind %q", kind)
+		}
+		exprs[kind] = regex
+	}
+	return c.extract(text, exprs, entropy, 0), nil
+}
+
+func (c *Contextualizer) extract(text string, exprs map[string]*regexp.Regexp, entropy *EntropyConfig, flags ScanFlag) map[string][]Match {
+	if c.Workers > 1 {
+		return c.extractParallel(text, exprs, entropy, flags)
+	}
 	results := make(map[string][]Match)
-	urlRanges := []struct{ start, end int }{}
-
-	// Handle URLs first to avoid partial matches in other types
-	if urlRegex, ok := c.Expressions["url"]; ok {
-		indices := urlRegex.FindAllStringIndex(text, -1)
-		seen := make(map[string]bool)
-		for _, idx := range indices {
-			val := strings.TrimSuffix(strings.TrimRight(text[idx[0]:idx[1]], "/.,;:"), "/")
-			cleanVal := strings.ToLower(val)
-
-			if u, err := url.Parse(cleanVal); err == nil && c.isDomainIgnored(u.Hostname()) {
-				continue
+	c.scan(text, exprs, entropy, flags, func(m Match) bool {
+		results[m.Type] = append(results[m.Type], m)
+		return true
+	})
+	return results
+}
+
+// scan runs exprs over text and hands every accepted match to yield, in the
+// order they are found, until yield returns false. Derived matches are
+// yielded right before the match they came from. It reports whether
+// c.Limits cut the scan short.
+func (c *Contextualizer) scan(text string, exprs map[string]*regexp.Regexp, entropy *EntropyConfig, flags ScanFlag, yield func(Match) bool) bool {
+	return c.scanStats(text, exprs, entropy, flags, nil, yield)
+}
+
+// scanStats is scan recording the bytes scanned and the time spent per
+// expression in stats, when it is non-nil.
+func (c *Contextualizer) scanStats(text string, exprs map[string]*regexp.Regexp, entropy *EntropyConfig, flags ScanFlag, stats *Stats, yield func(Match) bool) bool {
+	lim := c.newLimiter()
+	src := c.newSource(lim.clip(text), flags)
+	if stats != nil {
+		stats.BytesScanned = len(src.orig)
+		src.suppressed = stats.Suppressed
+	}
+
+	out := func(m Match) bool {
+		m, keep := c.filter(m)
+		if !keep {
+			return true
+		}
+		keep, more := lim.admit(m)
+		if keep && !yield(m) {
+			return false
+		}
+		return more
+	}
+	// The entropy detector needs everything else that was found, and the
+	// base64 pass the keys of what was reported.
+	var found []Match
+	var encoded map[string]bool
+	if c.Base64 != nil && !src.noDedup {
+		encoded = make(map[string]bool)
+	}
+	emit := func(m Match) bool {
+		if entropy != nil {
+			found = append(found, m)
+		}
+		if encoded != nil {
+			encoded[c.encodedKey(m)] = true
+		}
+		return out(m)
+	}
+
+	if !c.scanExprs(src, exprs, c.newResolver(), stats, emit) {
+		return lim.truncated
+	}
+	if entropy != nil {
+		done := stats.time("secret_candidate")
+		more := c.scanEntropy(src, entropy, found, out)
+		done()
+		if !more {
+			return lim.truncated
+		}
+	}
+	if c.Base64 != nil {
+		done := stats.time("base64")
+		c.scanBase64(src, exprs, encoded, out)
+		done()
+	}
+	return lim.truncated
+}
+
+// scanExprs runs URLs and then every other kind of exprs over src, in the
+// order of res. It reports whether yield wants more.
+func (c *Contextualizer) scanExprs(src source, exprs map[string]*regexp.Regexp, res *resolver, stats *Stats, yield func(Match) bool) bool {
+	done := stats.time("url")
+	ok := c.scanURLs(src, exprs, res, yield)
+	done()
+	if !ok {
+		return false
+	}
+	ranked, rest := res.order(exprs)
+	for _, kind := range append(ranked, rest...) {
+		done := stats.time(kind)
+		ok := c.scanKind(src, kind, exprs[kind], res, yield)
+		done()
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// scanURLs handles URLs first to avoid partial matches in other types, and
+// claims their spans in res. It reports whether yield wants more.
+func (c *Contextualizer) scanURLs(src source, exprs map[string]*regexp.Regexp, res *resolver, yield func(Match) bool) bool {
+	urlRegex, ok := exprs["url"]
+	if !ok || !c.mayMatch(urlRegex, src.text) {
+		return true
+	}
+
+	indices := findAll(urlRegex, src.text)
+	seen := getSeen()
+	defer putSeen(seen)
+	// Equivalent URLs are held back until the scan is over, so the forms
+	// found later can still be recorded as Aliases of the first.
+	var held []Match
+	var heldAt map[string]int
+	if c.EquivalentURLs && !src.noDedup {
+		heldAt = make(map[string]int)
+	}
+	for _, idx := range indices {
+		val := trimURL(src.text[idx[0]:idx[1]])
+		end := idx[0] + len(val)
+		if isMailto(val) {
+			if _, ok := exprs["email"]; ok && !c.scanMailto(src, val, idx[0], res, yield) {
+				return false
 			}
+			continue
+		}
+		if c.CanonicalURLs {
+			val = CanonicalURL(val)
+		}
+		cleanVal := strings.ToLower(val)
+		key := c.dedupKey("url", val, cleanVal)
 
-			if !seen[cleanVal] {
-				urlRanges = append(urlRanges, struct{ start, end int }{idx[0], idx[1]})
-				results["url"] = append(results["url"], Match{Value: val, Type: "url"})
-				seen[cleanVal] = true
+		if !seen[key] && !c.accept(src, "url", val, cleanVal) {
+			continue
+		}
+		res.claim("url", idx[0], end)
+		if seen[key] && !src.noDedup {
+			if i, ok := heldAt[key]; ok {
+				held[i] = addAlias(held[i], val)
 			}
+			continue
+		}
+		m, children := c.newMatch(src, "url", val, idx[0], end)
+		seen[key] = true
+		if heldAt != nil {
+			held = append(held, children...)
+			heldAt[key] = len(held)
+			held = append(held, m)
+			continue
+		}
+		for _, child := range children {
+			if !yield(child) {
+				return false
+			}
+		}
+		if !yield(m) {
+			return false
 		}
 	}
+	for _, m := range held {
+		if !yield(m) {
+			return false
+		}
+	}
+	return true
+}
 
-	for kind, regex := range c.Expressions {
-		if kind == "url" {
+// scanKind runs a single non-URL expression, claiming the spans of its
+// accepted matches in res, and reports whether yield wants more matches.
+// A nil regex stands for the Extractor registered under kind.
+func (c *Contextualizer) scanKind(src source, kind string, regex *regexp.Regexp, res *resolver, yield func(Match) bool) bool {
+	if regex == nil {
+		return c.scanExtractor(src, kind, res, yield)
+	}
+	if !c.mayMatch(regex, src.text) {
+		return true
+	}
+	rawMatches := findAll(regex, src.text)
+	seen := getSeen()
+	defer putSeen(seen)
+	if kind == "email" {
+		maps.Copy(seen, res.mailto)
+	}
+
+	for _, idx := range rawMatches {
+		if kind == "domain" && c.fileLike(src.text, idx[0], idx[1]) {
 			continue
 		}
+		val := canonicalize(kind, src.text[idx[0]:idx[1]])
+		if !c.consider(src, kind, val, idx[0], idx[1], nil, seen, res, yield) {
+			return false
+		}
+	}
+	return true
+}
 
-		rawMatches := regex.FindAllStringIndex(text, -1)
-		seen := make(map[string]bool)
+// consider emits the candidate val found at src.text[start:end] unless it
+// is shadowed, rejected or a repeat, and reports whether yield wants more.
+// meta, when non-nil, replaces the metadata of the match.
+func (c *Contextualizer) consider(src source, kind, val string, start, end int, meta map[string]string, seen map[string]bool, res *resolver, yield func(Match) bool) bool {
+	cleanVal := strings.ToLower(val)
+	key := c.dedupKey(kind, val, cleanVal)
 
-		for _, idx := range rawMatches {
-			val := text[idx[0]:idx[1]]
-			cleanVal := strings.ToLower(val)
+	if res.shadowed(kind, start, end) {
+		return true
+	}
+	// A repeated value was accepted the first time, but every
+	// occurrence claims its span.
+	if !seen[key] && !c.accept(src, kind, val, cleanVal) {
+		return true
+	}
+	res.claim(kind, start, end)
+	if seen[key] && !src.noDedup {
+		return true
+	}
+	m, children := c.newMatch(src, kind, val, start, end)
+	if meta != nil {
+		m.Metadata = meta
+	}
+	for _, child := range children {
+		if !yield(child) {
+			return false
+		}
+	}
 
-			// Basic overlap prevention
-			isInsideUrl := false
-			for _, r := range urlRanges {
-				if idx[0] >= r.start && idx[1] <= r.end {
-					isInsideUrl = true
-					break
-				}
-			}
-			if isInsideUrl {
-				continue
-			}
+	seen[key] = true
+	return yield(m)
+}
+
+// scanEntropy runs the entropy detector, if any, over what the expressions
+// left unclaimed, and reports whether yield wants more.
+func (c *Contextualizer) scanEntropy(src source, entropy *EntropyConfig, found []Match, yield func(Match) bool) bool {
+	if entropy == nil {
+		return true
+	}
+	for _, m := range entropy.find(src.text, found) {
+		m.Raw = src.raw(m.Start, m.End)
+		m.Start, m.End = src.offset(m.Start), src.offset(m.End)
+		m.Context = c.matchContext(src.orig, m.Start, m.End)
+		m.Line, m.Column = src.position(m.Start)
+		if !yield(m) {
+			return false
+		}
+	}
+	return true
+}
 
-			if seen[cleanVal] {
-				continue
+// findAll returns the [start, end] offsets of every match of regex in text.
+// When the expression has capture groups, the first group that took part
+// in the match delimits the value, which lets expressions anchor on
+// surrounding text they don't emit, in each branch of an alternation.
+func findAll(regex *regexp.Regexp, text string) [][]int {
+	if regex.NumSubexp() == 0 {
+		return regex.FindAllStringIndex(text, -1)
+	}
+	var out [][]int
+	for _, m := range regex.FindAllStringSubmatchIndex(text, -1) {
+		for g := 2; g < len(m); g += 2 {
+			if m[g] >= 0 {
+				out = append(out, m[g:g+2])
+				break
 			}
+		}
+	}
+	return out
+}
+
+// allowed reports whether a candidate of the given kind survives the
+// ignore lists and the per-kind sanity checks.
+func (c *Contextualizer) allowed(checks *PrivateChecks, kind, val, cleanVal string) bool {
+	if checks.patternIgnored(kind, val, cleanVal) {
+		return false
+	}
+	switch kind {
+	case "url":
+		if u, err := url.Parse(cleanVal); err == nil && checks.domainIgnored(u.Hostname()) {
+			return false
+		}
+	case "unc":
+		if checks.domainIgnored(uncHost(cleanVal)) {
+			return false
+		}
+	case "filename":
+		// Domains are scanned first and shadow filenames, but an ignored
+		// domain leaves its span unclaimed.
+		if checks.domainIgnored(cleanVal) {
+			return false
+		}
+	case "filepath":
+		if strings.HasPrefix(cleanVal, "http") || strings.HasPrefix(cleanVal, "www") || strings.HasPrefix(cleanVal, "ftp") {
+			return false
+		}
+		// Version strings, ratios and dates like 1.2/3.4 or 12/25/2024.
+		if !strings.ContainsFunc(val, unicode.IsLetter) {
+			return false
+		}
+	case "ipv4":
+		// Drops out-of-range octets and version strings like 10.2.300.4.
+		addr, err := netip.ParseAddr(val)
+		if err != nil || !addr.Is4() || checks.addrIgnored(addr) {
+			return false
+		}
+	case "email":
+		if _, exists := checks.IgnoredEmails[asciiEmail(cleanVal)]; exists {
+			return false
+		}
+		parts := strings.Split(cleanVal, "@")
+		if len(parts) == 2 && checks.domainIgnored(parts[1]) {
+			return false
+		}
+	case "domain":
+		if checks.domainIgnored(cleanVal) {
+			return false
+		}
+		if c.RequireKnownTLD && !KnownTLD(asciiDomain(cleanVal)) {
+			return false
+		}
+	case "btc":
+		if !validBitcoinAddress(val) {
+			return false
+		}
+	case "ipport":
+		ap, err := netip.ParseAddrPort(val)
+		if err != nil || ap.Port() == 0 || checks.addrIgnored(ap.Addr()) {
+			return false
+		}
+	case "obfuscated_ipv4":
+		addr, _, ok := parseObfuscatedIPv4(val)
+		if !ok || checks.addrIgnored(addr) {
+			return false
+		}
+	case "timestamp":
+		if _, _, ok := parseTimestamp(val); !ok {
+			return false
+		}
+	case "cidr":
+		prefix, err := netip.ParsePrefix(val)
+		if err != nil || checks.prefixIgnored(prefix) {
+			return false
+		}
+	case "md5", "sha1", "sha256", "sha512":
+		if checks.hashIgnored(cleanVal) {
+			return false
+		}
+	case "ipv6":
+		// Requiring a digit keeps out "::" and identifiers like "dead::beef".
+		addr, err := netip.ParseAddr(val)
+		if err != nil || !strings.ContainsAny(val, "0123456789") || checks.addrIgnored(addr) {
+			return false
+		}
+	case "mac":
+		if checks.IgnoreLocalMACs && isLocalMAC(val) {
+			return false
+		}
+	case "jwt":
+		if _, _, ok := decodeJWT(val); !ok {
+			return false
+		}
+	case "pem":
+		if block, _ := pem.Decode([]byte(val)); block == nil {
+			return false
+		}
+	case "ssh_key":
+		if _, _, _, ok := parseSSHKey(val); !ok {
+			return false
+		}
+	case "credit_card":
+		if !luhnValid(val) || cardIssuer(val) == "" {
+			return false
+		}
+	case "eth":
+		if c.VerifyEIP55 && !validEIP55(val) {
+			return false
+		}
+	case "ssn":
+		if !validSSN(val) {
+			return false
+		}
+	case "iban":
+		if !validIBAN(val) {
+			return false
+		}
+	}
+	if id, ok := c.nationalID(kind); ok && !id.Valid(val) {
+		return false
+	}
+	return c.validate(kind, val)
+}
+
+// accept is allowed for the scan of src. When src counts suppressions,
+// values rejected only by the ignore lists are tallied per kind.
+func (c *Contextualizer) accept(src source, kind, val, cleanVal string) bool {
+	if c.allowed(src.checks, kind, val, cleanVal) {
+		return true
+	}
+	if src.suppressed != nil && src.checks != noChecks && c.allowed(noChecks, kind, val, cleanVal) {
+		src.suppressed[kind]++
+	}
+	return false
+}
+
+// metadata returns the kind-specific details attached to an emitted match,
+// or nil when there are none.
+func (c *Contextualizer) metadata(kind, val string) map[string]string {
+	switch kind {
+	case "jwt":
+		if !c.DecodeJWT {
+			return nil
+		}
+		header, payload, ok := decodeJWT(val)
+		if !ok {
+			return nil
+		}
+		return map[string]string{"header": header, "payload": payload}
+	case "pem":
+		if block, _ := pem.Decode([]byte(val)); block != nil {
+			return map[string]string{"block_type": block.Type, "fingerprint": pemFingerprint(block)}
+		}
+	case "url":
+		if c.DecomposeURLs {
+			return urlComponents(val)
+		}
+	case "email":
+		return emailParts(val)
+	case "obfuscated_ipv4":
+		if _, encoding, ok := parseObfuscatedIPv4(val); ok {
+			return map[string]string{"encoding": encoding}
+		}
+	case "timestamp":
+		if t, format, ok := parseTimestamp(val); ok {
+			return map[string]string{"format": format, "time": formatTimestamp(t)}
+		}
+	case "credit_card":
+		return map[string]string{"issuer": cardIssuer(val)}
+	case "iban":
+		return map[string]string{"country": val[:2]}
+	case "ssh_key":
+		if keyType, comment, fingerprint, ok := parseSSHKey(val); ok {
+			return map[string]string{"key_type": keyType, "comment": comment, "fingerprint": fingerprint}
+		}
+	}
+	return nil
+}
 
-			switch kind {
-			case "filepath":
-				if strings.HasPrefix(cleanVal, "http") || strings.HasPrefix(cleanVal, "www") {
-					continue
-				}
-			case "ipv4":
-				if c.Checks.IgnorePrivateIPs && isPrivateIP(val) {
-					continue
-				}
-			case "email":
-				if _, exists := c.Checks.IgnoredEmails[cleanVal]; exists {
-					continue
-				}
-				parts := strings.Split(cleanVal, "@")
-				if len(parts) == 2 && c.isDomainIgnored(parts[1]) {
-					continue
-				}
-			case "domain":
-				if c.isDomainIgnored(cleanVal) {
-					continue
-				}
-				// Add base domain for consistency with GetMatches
-				base, err := extractSecondLevelDomain(cleanVal)
-				if err == nil && base != "" && base != cleanVal {
-					if !c.isDomainIgnored(base) {
-						results["base_domain"] = append(results["base_domain"], Match{Value: base, Type: "base_domain"})
-					}
-				}
+// newMatch builds the match for an accepted value found at
+// src.text[start:end], along with the matches derived from it. Children
+// share the offsets of the text they were derived from.
+func (c *Contextualizer) newMatch(src source, kind, val string, start, end int) (Match, []Match) {
+	m := Match{
+		Value:    c.output(kind, val),
+		Type:     retype(kind, src.text, start),
+		Raw:      src.raw(start, end),
+		Start:    src.offset(start),
+		End:      src.offset(end),
+		Metadata: c.metadata(kind, val),
+	}
+	if kind == "sha1" && c.DetectGitCommits && gitCommitContext(src.text, start, end) {
+		m.Type = "git_commit"
+	}
+	hint, hinted := c.keywordHint(kind, src.text, start)
+	if hinted && hint.Type != "" {
+		m.Type = hint.Type
+	}
+	if c.PreserveCase || c.Normalize || isIDN(kind, val) {
+		m.Normalized = c.output(kind, c.normalize(kind, val))
+	}
+	if c.PreserveCase {
+		m.Value = c.output(kind, src.text[start:end])
+	}
+	m.Context = c.matchContext(src.orig, m.Start, m.End)
+	m.Line, m.Column = src.position(m.Start)
+	lead := src.text[max(0, start-contextWindow):start]
+	if c.ScoreMatches {
+		m.Confidence = c.confidence(m.Type, val, lead)
+		switch m.Type {
+		case "md5", "sha1", "sha256", "sha512":
+			if c.HashHeuristics {
+				m.Confidence = max(0, m.Confidence-hashNoise(src.text, start, end))
 			}
+		}
+		if hinted {
+			m.Confidence = min(1, max(0, m.Confidence+hint.Boost))
+		}
+	}
+	children := c.children(src.checks, kind, val)
+	for i := range children {
+		if c.ScoreMatches {
+			children[i].Confidence = c.confidence(children[i].Type, children[i].Value, lead)
+		}
+		children[i].Start, children[i].End = m.Start, m.End
+		children[i].Line, children[i].Column = m.Line, m.Column
+		children[i].Context = m.Context
+	}
+	return m, children
+}
 
-			seen[cleanVal] = true
-			results[kind] = append(results[kind], Match{Value: val, Type: kind})
+// children returns the matches derived from an accepted match, such as the
+// base domain of a domain or the host of a UNC path.
+func (c *Contextualizer) children(checks *PrivateChecks, kind, val string) []Match {
+	var out []Match
+	switch kind {
+	case "domain":
+		if base, ok := c.baseDomain(checks, strings.ToLower(val)); ok {
+			out = append(out, Match{Value: c.output("base_domain", base), Type: "base_domain", Parent: c.output(kind, val)})
+		}
+	case "ipport":
+		ap, err := netip.ParseAddrPort(val)
+		if err != nil {
+			break
 		}
+		ip := ap.Addr().WithZone("").String()
+		out = append(out,
+			Match{Value: c.output(hostKind(ip), ip), Type: hostKind(ip), Parent: c.output(kind, val)},
+			Match{Value: strconv.Itoa(int(ap.Port())), Type: "port", Parent: c.output(kind, val)},
+		)
+	case "obfuscated_ipv4":
+		if addr, _, ok := parseObfuscatedIPv4(val); ok {
+			out = append(out, Match{Value: c.output("ipv4", addr.String()), Type: "ipv4", Parent: c.output(kind, val)})
+		}
+	case "url":
+		if !c.DecomposeURLs {
+			break
+		}
+		if host := urlHost(val); host != "" {
+			out = append(out, Match{Value: c.output(hostKind(host), host), Type: hostKind(host), Parent: c.output(kind, val)})
+		}
+	case "unc":
+		host := uncHost(val)
+		out = append(out, Match{Value: c.output(hostKind(host), strings.ToLower(host)), Type: hostKind(host), Parent: c.output(kind, val)})
 	}
-	return results
+	return out
 }
 
-func (c *Contextualizer) isDomainIgnored(domain string) bool {
-	current := strings.TrimSuffix(strings.ToLower(domain), ".")
+// baseDomain returns the registrable domain of a domain match when it
+// differs from the match itself and is not ignored. A name with a single
+// dot, such as "example.com", is either its own registrable domain or a
+// public suffix, so it never has a distinct base and skips the lookup.
+func (c *Contextualizer) baseDomain(checks *PrivateChecks, domain string) (string, bool) {
+	if strings.Count(domain, ".") < 2 {
+		return "", false
+	}
+	base, err := extractSecondLevelDomain(domain)
+	if err != nil || base == "" || base == domain || checks.domainIgnored(base) {
+		return "", false
+	}
+	return base, true
+}
+
+// canonicalize rewrites values of kinds that have several equivalent
+// spellings into a single form, so they deduplicate and compare cleanly.
+func canonicalize(kind, val string) string {
+	switch kind {
+	case "mac":
+		if hw, err := net.ParseMAC(val); err == nil {
+			return hw.String()
+		}
+	case "ipv6":
+		if addr, err := netip.ParseAddr(val); err == nil {
+			return addr.String()
+		}
+	case "ipport":
+		if ap, err := netip.ParseAddrPort(val); err == nil {
+			return ap.String()
+		}
+	case "credit_card":
+		return strings.NewReplacer(" ", "", "-", "").Replace(val)
+	case "iban":
+		return strings.ReplaceAll(val, " ", "")
+	case "winpath":
+		// Paths copied out of JSON or source code carry escaped backslashes.
+		return strings.ReplaceAll(val, `\\`, `\`)
+	}
+	return val
+}
+
+// output prepares a value of the given kind for emission according to the
+// output options.
+func (c *Contextualizer) output(kind, value string) string {
+	if kind == "pem" && c.RedactPEM {
+		return redactPEM(value)
+	}
+	if c.DefangOutput {
+		return Defang(value)
+	}
+	return value
+}
+
+// trimURL strips what prose wraps around a URL from the end of a url
+// candidate: anything from a quote, angle bracket, backtick or the "]("
+// of a Markdown link on, trailing punctuation, and closing brackets that
+// have no opening partner inside the URL, so "(see http://x/a_(b))." keeps
+// one parenthesis. A final slash is dropped.
+func trimURL(u string) string {
+	if i := strings.IndexAny(u, "\"<>`"); i >= 0 {
+		u = u[:i]
+	}
+	if i := strings.Index(u, "]("); i >= 0 {
+		u = u[:i]
+	}
+	for {
+		trimmed := strings.TrimRight(u, "/.,;:!?'*")
+		if n := len(trimmed); n > 0 {
+			if open, ok := urlBrackets[trimmed[n-1]]; ok && strings.Count(trimmed, string(open)) < strings.Count(trimmed, trimmed[n-1:]) {
+				trimmed = trimmed[:n-1]
+			}
+		}
+		if trimmed == u {
+			return strings.TrimSuffix(u, "/")
+		}
+		u = trimmed
+	}
+}
+
+// urlBrackets maps the closing brackets trimURL balances to their
+// opening ones.
+var urlBrackets = map[byte]byte{')': '(', ']': '[', '}': '{'}
+
+func (p *PrivateChecks) domainIgnored(domain string) bool {
+	domain = asciiDomain(domain)
+	current := domain
 	for {
-		if _, exists := c.Checks.IgnoredDomains[current]; exists {
+		if _, exists := p.IgnoredDomains[current]; exists {
 			return true
 		}
 		idx := strings.Index(current, ".")
@@ -225,17 +1049,42 @@ func (c *Contextualizer) isDomainIgnored(domain string) bool {
 		}
 		current = current[idx+1:]
 	}
-	return false
+	return p.topDomain(domain)
 }
 
-func isPrivateIP(ipStr string) bool {
-	ip := net.ParseIP(ipStr)
-	if ip == nil {
-		return false
+// isPrivateIP reports whether addr is private (RFC 1918 or an IPv6 unique
+// local address), loopback or link-local.
+func isPrivateIP(addr netip.Addr) bool {
+	addr = addr.WithZone("").Unmap()
+	return addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast()
+}
+
+// uncHost returns the server component of a \\server\share path.
+func uncHost(path string) string {
+	host, _, _ := strings.Cut(strings.TrimLeft(path, `\`), `\`)
+	return host
+}
+
+// hostKind classifies a bare host name as ipv4, ipv6, domain or hostname.
+func hostKind(host string) string {
+	if ip := net.ParseIP(host); ip != nil {
+		if ip.To4() != nil {
+			return "ipv4"
+		}
+		return "ipv6"
 	}
-	return ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast()
+	if strings.Contains(host, ".") {
+		return "domain"
+	}
+	return "hostname"
 }
 
-func extractSecondLevelDomain(domain string) (string, error) {
-	return publicsuffix.EffectiveTLDPlusOne(domain)
+// isLocalMAC reports whether the address is locally administered or
+// multicast, i.e. not a globally unique vendor-assigned address.
+func isLocalMAC(mac string) bool {
+	hw, err := net.ParseMAC(mac)
+	if err != nil || len(hw) == 0 {
+		return false
+	}
+	return hw[0]&0x03 != 0
 }
@@ -7,7 +7,7 @@ import (
 )
 
 func TestContextualizer_IgnoreLogic(t *testing.T) {
-	c := NewContextualizer(true, []string{"nullferatu.com", "google.com"}, []string{"admin@test.com"})
+	c := NewContextualizerWithIgnores(true, []string{"nullferatu.com", "google.com"}, []string{"admin@test.com"})
 
 	tests := []struct {
 		name     string
@@ -43,7 +43,7 @@ func TestContextualizer_IgnoreLogic(t *testing.T) {
 			name:     "Allow non-ignored domain",
 			input:    "Visit example.com",
 			kind:     "domain",
-			expected: []Match{{Value: "example.com", Type: "domain"}},
+			expected: []Match{{Value: "example.com", Type: "domain", Start: 6, End: 17}},
 		},
 		{
 			name:     "Ignore private IP",
@@ -55,7 +55,13 @@ func TestContextualizer_IgnoreLogic(t *testing.T) {
 			name:     "Allow public IP",
 			input:    "IP is 8.8.8.8",
 			kind:     "ipv4",
-			expected: []Match{{Value: "8.8.8.8", Type: "ipv4"}},
+			expected: []Match{{Value: "8.8.8.8", Type: "ipv4", Start: 6, End: 13}},
+		},
+		{
+			name:     "Drop invalid octets",
+			input:    "Upgraded to 10.2.300.4, scanned 999.999.999.999 and 01.2.3.4",
+			kind:     "ipv4",
+			expected: nil,
 		},
 	}
 
@@ -71,7 +77,7 @@ func TestContextualizer_IgnoreLogic(t *testing.T) {
 }
 
 func TestContextualizer_ExtractAll(t *testing.T) {
-	c := NewContextualizer(false, []string{"ignored.com"}, nil)
+	c := NewContextualizer(WithIgnoredDomains("ignored.com"))
 	text := "Check out http://example.com/page, contact dev@example.com or visit sub.test.org"
 
 	results := c.ExtractAll(text)
@@ -91,3 +97,188 @@ func TestContextualizer_ExtractAll(t *testing.T) {
 		t.Errorf("Base domain 'test.org' not extracted from 'sub.test.org'")
 	}
 }
+
+func TestContextualizer_Defanged(t *testing.T) {
+	c := NewContextualizer()
+	c.MatchDefanged = true
+	text := "C2 at hxxps://evil[.]com/gate and 1.2.3[.]4, mail bad[@]evil(dot)org"
+
+	results := c.ExtractAll(text)
+
+	want := Match{Value: "https://evil.com/gate", Type: "url", Raw: "hxxps://evil[.]com/gate", Start: 6, End: 29}
+	if len(results["url"]) != 1 || !reflect.DeepEqual(results["url"][0], want) {
+		t.Errorf("URL extraction failed: %v", results["url"])
+	}
+	if len(results["ipv4"]) != 1 || results["ipv4"][0].Value != "1.2.3.4" || results["ipv4"][0].Raw != "1.2.3[.]4" {
+		t.Errorf("IPv4 extraction failed: %v", results["ipv4"])
+	}
+	if len(results["email"]) != 1 || results["email"][0].Value != "bad@evil.org" {
+		t.Errorf("Email extraction failed: %v", results["email"])
+	}
+
+	c.MatchDefanged = false
+	if got := c.GetMatches(text, "ipv4", c.Expressions["ipv4"]); got != nil {
+		t.Errorf("Defanged IP matched with MatchDefanged off: %v", got)
+	}
+}
+
+func TestContextualizer_MAC(t *testing.T) {
+	c := NewContextualizer()
+	text := "Seen 00-1A-2B-3C-4D-5E, 001a.2b3c.4d5e and 02:00:00:aa:bb:cc"
+
+	got := c.GetMatches(text, "mac", c.Expressions["mac"])
+	expected := []Match{
+		{Value: "00:1a:2b:3c:4d:5e", Type: "mac", Start: 5, End: 22},
+		{Value: "02:00:00:aa:bb:cc", Type: "mac", Start: 43, End: 60},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("GetMatches() = %v, want %v", got, expected)
+	}
+
+	c.Checks.IgnoreLocalMACs = true
+	results := c.ExtractAll(text)
+	if len(results["mac"]) != 1 || results["mac"][0].Value != "00:1a:2b:3c:4d:5e" {
+		t.Errorf("MAC extraction failed: %v", results["mac"])
+	}
+}
+
+func TestContextualizer_WindowsPath(t *testing.T) {
+	c := NewContextualizer()
+	text := `Dropped C:\Users\foo\evil.exe. Persisted via %APPDATA%\Microsoft\run.bat and "C:\\Windows\\Temp\\x.dll"`
+
+	got := c.GetMatches(text, "winpath", c.Expressions["winpath"])
+	expected := []Match{
+		{Value: `C:\Users\foo\evil.exe`, Type: "winpath", Start: 8, End: 29},
+		{Value: `%APPDATA%\Microsoft\run.bat`, Type: "winpath", Start: 45, End: 72},
+		{Value: `C:\Windows\Temp\x.dll`, Type: "winpath", Start: 78, End: 102},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("GetMatches() = %v, want %v", got, expected)
+	}
+}
+
+func TestContextualizer_UNCPath(t *testing.T) {
+	c := NewContextualizer(WithIgnoredDomains("corp.example"))
+	text := `Staged on \\FS01.evil.net\share$\drop\run.ps1, copied from \\10.1.2.3\c$\tmp and \\files.corp.example\it`
+
+	got := c.GetMatches(text, "unc", c.Expressions["unc"])
+	expected := []Match{
+		{Value: "fs01.evil.net", Type: "domain", Start: 10, End: 45, Parent: `\\FS01.evil.net\share$\drop\run.ps1`},
+		{Value: `\\FS01.evil.net\share$\drop\run.ps1`, Type: "unc", Start: 10, End: 45},
+		{Value: "10.1.2.3", Type: "ipv4", Start: 59, End: 76, Parent: `\\10.1.2.3\c$\tmp`},
+		{Value: `\\10.1.2.3\c$\tmp`, Type: "unc", Start: 59, End: 76},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("GetMatches() = %v, want %v", got, expected)
+	}
+	// The hosts are only reported as children of their paths.
+	for _, workers := range []int{0, 4} {
+		c.Workers = workers
+		all := c.ExtractAll(text)
+		if d := all["domain"]; len(d) != 1 || d[0].Value != "fs01.evil.net" || d[0].Parent == "" {
+			t.Errorf("workers=%d: domain = %v", workers, d)
+		}
+		if ip := all["ipv4"]; len(ip) != 1 || ip[0].Parent != `\\10.1.2.3\c$\tmp` {
+			t.Errorf("workers=%d: ipv4 = %v", workers, ip)
+		}
+	}
+}
+
+func TestContextualizer_IPv6(t *testing.T) {
+	c := NewContextualizer()
+	text := "Hosts 2001:0DB8:0000:0000:0000:0000:0000:0001, fe80::1%eth0, ::ffff:192.0.2.1 and ::1 " +
+		"but not std::string, dead::beef or 12:30:45"
+
+	got := c.GetMatches(text, "ipv6", c.Expressions["ipv6"])
+	expected := []Match{
+		{Value: "2001:db8::1", Type: "ipv6", Start: 6, End: 45},
+		{Value: "fe80::1%eth0", Type: "ipv6", Start: 47, End: 59},
+		{Value: "::ffff:192.0.2.1", Type: "ipv6", Start: 61, End: 77},
+		{Value: "::1", Type: "ipv6", Start: 82, End: 85},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("GetMatches() = %v, want %v", got, expected)
+	}
+}
+
+func TestContextualizer_IPPort(t *testing.T) {
+	c := NewContextualizer(WithIgnorePrivateIPs(true))
+	text := "Beacons to 8.8.8.8:4444 and [2001:DB8::1]:443, local 10.0.0.1:22, bad 1.2.3.4:99999"
+
+	got := c.GetMatches(text, "ipport", c.Expressions["ipport"])
+	expected := []Match{
+		{Value: "8.8.8.8", Type: "ipv4", Start: 11, End: 23, Parent: "8.8.8.8:4444"},
+		{Value: "4444", Type: "port", Start: 11, End: 23, Parent: "8.8.8.8:4444"},
+		{Value: "8.8.8.8:4444", Type: "ipport", Start: 11, End: 23},
+		{Value: "2001:db8::1", Type: "ipv6", Start: 28, End: 45, Parent: "[2001:db8::1]:443"},
+		{Value: "443", Type: "port", Start: 28, End: 45, Parent: "[2001:db8::1]:443"},
+		{Value: "[2001:db8::1]:443", Type: "ipport", Start: 28, End: 45},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("GetMatches() = %v, want %v", got, expected)
+	}
+}
+
+func TestContextualizer_IPPortAddressOnce(t *testing.T) {
+	text := "dns 8.8.8.8:53 and [2001:db8::1]:443, then 8.8.4.4"
+	for _, workers := range []int{0, 4} {
+		c := NewContextualizer(WithWorkers(workers))
+		got := c.ExtractAll(text)
+		if v := values(got["ipv4"]); !reflect.DeepEqual(v, []string{"ipv4:8.8.8.8", "ipv4:8.8.4.4"}) {
+			t.Errorf("workers=%d: ipv4 = %v", workers, v)
+		}
+		if v := got["ipv6"]; len(v) != 1 || v[0].Parent != "[2001:db8::1]:443" {
+			t.Errorf("workers=%d: ipv6 = %v", workers, v)
+		}
+	}
+}
+
+func TestContextualizer_Offsets(t *testing.T) {
+	c := NewContextualizer(WithDefanged())
+	text := "C2 1.2.3[.]4 and hxxp://evil[.]com/x, hash d41d8cd98f00b204e9800998ecf8427e"
+
+	for kind, matches := range c.ExtractAll(text) {
+		for _, m := range matches {
+			raw := m.Raw
+			if raw == "" {
+				raw = m.Value
+			}
+			if kind != "base_domain" && text[m.Start:m.End] != raw {
+				t.Errorf("%s: text[%d:%d] = %q, want %q", kind, m.Start, m.End, text[m.Start:m.End], raw)
+			}
+		}
+	}
+}
+
+func TestTrimURL(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"http://evil.com/a.", "http://evil.com/a"},
+		{"http://evil.com/a),", "http://evil.com/a"},
+		{"https://en.wikipedia.org/wiki/Mirai_(malware))", "https://en.wikipedia.org/wiki/Mirai_(malware)"},
+		{"http://evil.com/x]", "http://evil.com/x"},
+		{"http://evil.com/a?q=[1]", "http://evil.com/a?q=[1]"},
+		{`http://evil.com/gate">click</a>`, "http://evil.com/gate"},
+		{"http://evil.com/a](http://evil.com/a)", "http://evil.com/a"},
+		{"http://evil.com/a'!", "http://evil.com/a"},
+		{"http://evil.com/>", "http://evil.com"},
+	}
+	for _, tt := range tests {
+		if got := trimURL(tt.in); got != tt.want {
+			t.Errorf("trimURL(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestContextualizer_URLsInProse(t *testing.T) {
+	c := NewContextualizer()
+	got := c.ExtractAll(`See (http://evil.com/a), <a href="http://bad.net/x">mirror.org</a>.`)
+
+	if u := got["url"]; len(u) != 2 || u[0].Value != "http://evil.com/a" || u[0].End != 22 || u[1].Value != "http://bad.net/x" {
+		t.Errorf("url = %v", u)
+	}
+	if d := got["domain"]; len(d) != 1 || d[0].Value != "mirror.org" {
+		t.Errorf("domain = %v", d)
+	}
+}
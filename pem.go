@@ -0,0 +1,25 @@
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"strings"
+)
+
+// pemFingerprint returns the hex sha256 of the DER bytes of a block.
+func pemFingerprint(block *pem.Block) string {
+	sum := sha256.Sum256(block.Bytes)
+	return hex.EncodeToString(sum[:])
+}
+
+// redactPEM keeps the BEGIN/END armor of a block and replaces everything
+// between them.
+func redactPEM(block string) string {
+	nl := strings.IndexByte(block, '\n')
+	end := strings.LastIndex(block, "-----END ")
+	if nl == -1 || end < nl {
+		return block
+	}
+	return block[:nl+1] + "REDACTED\n" + block[end:]
+}
@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// NationalID is a national identifier format that can be registered on a
+// Contextualizer alongside the built-in US SSN support of the pii profile.
+type NationalID interface {
+	// Kind is the match type emitted for the identifier, e.g. "uk_nino".
+	Kind() string
+	// Expression finds candidates in text.
+	Expression() *regexp.Regexp
+	// Valid performs the format-specific checks a regex cannot, such as
+	// check digits or reserved ranges.
+	Valid(value string) bool
+}
+
+// RegisterNationalID adds the expression of id under its kind and validates
+// every candidate of that kind with id.Valid before it is emitted.
+func (c *Contextualizer) RegisterNationalID(id NationalID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.nationalIDs == nil {
+		c.nationalIDs = make(map[string]NationalID)
+	}
+	c.nationalIDs[id.Kind()] = id
+	c.Expressions[id.Kind()] = id.Expression()
+}
+
+// validSSN applies the SSA rules to a dashed or spaced US SSN: area 000, 666
+// and 900-999, group 00 and serial 0000 are never issued.
+func validSSN(ssn string) bool {
+	digits := strings.NewReplacer("-", "", " ", "").Replace(ssn)
+	if len(digits) != 9 {
+		return false
+	}
+	area, group, serial := digits[:3], digits[3:5], digits[5:]
+	return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+}
+
+// luhnValid reports whether a digit string passes the Luhn checksum.
+func luhnValid(digits string) bool {
+	var sum int
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if d < 0 || d > 9 {
+			return false
+		}
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// cardIssuer classifies a card number by its IIN range and length, returning
+// "" for numbers that belong to no known issuer.
+func cardIssuer(digits string) string {
+	n := len(digits)
+	prefix := func(lo, hi string) bool {
+		p := digits[:len(lo)]
+		return p >= lo && p <= hi
+	}
+	switch {
+	case strings.HasPrefix(digits, "4") && (n == 13 || n == 16 || n == 19):
+		return "visa"
+	case n == 16 && (prefix("51", "55") || prefix("2221", "2720")):
+		return "mastercard"
+	case n == 15 && (prefix("34", "34") || prefix("37", "37")):
+		return "amex"
+	case n >= 16 && n <= 19 && (prefix("6011", "6011") || prefix("65", "65") || prefix("644", "649")):
+		return "discover"
+	case n >= 16 && n <= 19 && prefix("3528", "3589"):
+		return "jcb"
+	}
+	return ""
+}
+
+// validIBAN checks the length and ISO 7064 mod-97 check digits of a compact
+// (space-free, uppercase) IBAN.
+func validIBAN(iban string) bool {
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+	rearranged := iban[4:] + iban[:4]
+	var rem int
+	for i := 0; i < len(rearranged); i++ {
+		ch := rearranged[i]
+		switch {
+		case ch >= '0' && ch <= '9':
+			rem = (rem*10 + int(ch-'0')) % 97
+		case ch >= 'A' && ch <= 'Z':
+			rem = (rem*100 + int(ch-'A') + 10) % 97
+		default:
+			return false
+		}
+	}
+	return rem == 1
+}
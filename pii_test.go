@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestContextualizer_CreditCards(t *testing.T) {
+	c := NewContextualizer()
+	if err := c.EnableProfile("pii"); err != nil {
+		t.Fatalf("EnableProfile() error = %v", err)
+	}
+	text := "Cards: 4111 1111 1111 1111, 5500-0000-0000-0004, 378282246310005; bad 4111 1111 1111 1112, order 1234567890123"
+
+	got := c.GetMatches(text, "credit_card", c.Expressions["credit_card"])
+	expected := []Match{
+		{Value: "4111111111111111", Type: "credit_card", Start: 7, End: 26, Metadata: map[string]string{"issuer": "visa"}},
+		{Value: "5500000000000004", Type: "credit_card", Start: 28, End: 47, Metadata: map[string]string{"issuer": "mastercard"}},
+		{Value: "378282246310005", Type: "credit_card", Start: 49, End: 64, Metadata: map[string]string{"issuer": "amex"}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("GetMatches() = %v, want %v", got, expected)
+	}
+}
+
+func TestLuhnValid(t *testing.T) {
+	for digits, want := range map[string]bool{
+		"79927398713":      true,
+		"79927398710":      false,
+		"4111111111111111": true,
+	} {
+		if got := luhnValid(digits); got != want {
+			t.Errorf("luhnValid(%q) = %v, want %v", digits, got, want)
+		}
+	}
+}
+
+type ninoID struct{}
+
+func (ninoID) Kind() string { return "uk_nino" }
+
+func (ninoID) Expression() *regexp.Regexp {
+	return regexp.MustCompile(`\b([A-Z]{2}\d{6}[A-D])\b`)
+}
+
+func (ninoID) Valid(value string) bool {
+	return !strings.ContainsAny(value[:2], "DFIQUV")
+}
+
+func TestContextualizer_NationalIDs(t *testing.T) {
+	c := NewContextualizer()
+	if err := c.EnableProfile("pii"); err != nil {
+		t.Fatalf("EnableProfile() error = %v", err)
+	}
+	c.RegisterNationalID(ninoID{})
+	text := "SSNs 078-05-1120, 666-12-3456, 123-00-4567; NINO AB123456C and DA123456C"
+
+	results := c.ExtractAll(text)
+
+	if got := results["ssn"]; len(got) != 1 || got[0].Value != "078-05-1120" {
+		t.Errorf("ssn extraction failed: %v", got)
+	}
+	if got := results["uk_nino"]; len(got) != 1 || got[0].Value != "AB123456C" {
+		t.Errorf("uk_nino extraction failed: %v", got)
+	}
+}
+
+func TestContextualizer_IBAN(t *testing.T) {
+	c := NewContextualizer()
+	if err := c.EnableProfile("pii"); err != nil {
+		t.Fatalf("EnableProfile() error = %v", err)
+	}
+	text := "Wire to DE89 3704 0044 0532 0130 00 or GB82WEST12345698765432, not DE89 3704 0044 0532 0130 01"
+
+	got := c.GetMatches(text, "iban", c.Expressions["iban"])
+	expected := []Match{
+		{Value: "DE89370400440532013000", Type: "iban", Start: 8, End: 35, Metadata: map[string]string{"country": "DE"}},
+		{Value: "GB82WEST12345698765432", Type: "iban", Start: 39, End: 61, Metadata: map[string]string{"country": "GB"}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("GetMatches() = %v, want %v", got, expected)
+	}
+}
@@ -0,0 +1,137 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// prefilter reports whether an expression could possibly match text. It
+// must never return false for text the expression matches.
+type prefilter func(text string) bool
+
+func containsAny(subs ...string) prefilter {
+	return func(text string) bool {
+		for _, sub := range subs {
+			if strings.Contains(text, sub) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func containsBytes(chars string) prefilter {
+	return func(text string) bool {
+		return strings.ContainsAny(text, chars)
+	}
+}
+
+// containsFold is containsAny for a single ASCII sub, ignoring case.
+func containsFold(sub string) prefilter {
+	return func(text string) bool {
+		for i := 0; i+len(sub) <= len(text); i++ {
+			if strings.EqualFold(text[i:i+len(sub)], sub) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// anyOf passes text that any of pfs passes.
+func anyOf(pfs ...prefilter) prefilter {
+	return func(text string) bool {
+		for _, pf := range pfs {
+			if pf(text) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// hexRun matches text holding at least n consecutive hex digits.
+func hexRun(n int) prefilter {
+	return func(text string) bool {
+		run := 0
+		for i := 0; i < len(text); i++ {
+			if isHexByte(text[i]) {
+				run++
+				if run >= n {
+					return true
+				}
+			} else {
+				run = 0
+			}
+		}
+		return false
+	}
+}
+
+func isHexByte(b byte) bool {
+	return b >= '0' && b <= '9' || b >= 'a' && b <= 'f' || b >= 'A' && b <= 'F'
+}
+
+// builtinPrefilters are the cheap checks for the built-in and profile
+// expressions. They are attached to the compiled expressions themselves, so
+// a custom expression registered under the same kind is never skipped.
+var builtinPrefilters = map[string]prefilter{
+	"md5":      hexRun(32),
+	"sha1":     hexRun(40),
+	"sha256":   hexRun(64),
+	"sha512":   hexRun(128),
+	"jarm":     hexRun(62),
+	"ja4":      containsBytes("_"),
+	"eth":      containsAny("0x", "0X"),
+	"ipv4":     containsBytes("."),
+	"ipv6":     containsBytes(":"),
+	"ipport":   containsBytes(":"),
+	"mac":      containsBytes(":-."),
+	"email":    containsBytes("@"),
+	"url":      anyOf(containsAny("://"), containsFold("mailto:")),
+	"domain":   containsBytes("."),
+	"filepath": containsBytes("/"),
+	"filename": containsBytes("."),
+	"winpath":  containsBytes(`\`),
+	"unc":      containsAny(`\\`),
+	"jwt":      containsAny("eyJ"),
+	"ssh_key":  containsAny("ssh-", "ecdsa-", "sk-"),
+
+	"aws_access_key":          containsAny("AKIA", "ASIA", "AGPA", "AIDA", "AROA", "AIPA", "ANPA", "ANVA"),
+	"gcp_api_key":             containsAny("AIza"),
+	"github_token":            containsAny("gh", "github_pat_"),
+	"gitlab_token":            containsAny("glpat-"),
+	"slack_token":             containsAny("xox"),
+	"slack_webhook":           containsAny("hooks.slack.com"),
+	"discord_webhook":         containsAny("/api/webhooks/"),
+	"pem":                     containsAny("-----BEGIN "),
+	"azure_connection_string": containsAny("AccountKey=", "SharedAccessKey="),
+	"ssn":                     containsBytes("- "),
+	"timestamp":               containsBytes(":="),
+}
+
+// attachPrefilters records the built-in prefilter of every kind in exprs
+// under its compiled expression. The caller must hold c.mu if the
+// Contextualizer is shared.
+func (c *Contextualizer) attachPrefilters(exprs map[string]*regexp.Regexp) {
+	if c.prefilters == nil {
+		c.prefilters = make(map[*regexp.Regexp]prefilter)
+	}
+	for kind, regex := range exprs {
+		if pf, ok := builtinPrefilters[kind]; ok {
+			c.prefilters[regex] = pf
+		}
+	}
+}
+
+// mayMatch reports whether regex could match text, according to the
+// prefilter attached to it. Expressions without one always may.
+func (c *Contextualizer) mayMatch(regex *regexp.Regexp, text string) bool {
+	if c.DisablePrefilter {
+		return true
+	}
+	c.mu.RLock()
+	pf, ok := c.prefilters[regex]
+	c.mu.RUnlock()
+	return !ok || pf(text)
+}
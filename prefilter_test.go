@@ -0,0 +1,44 @@
+package parser
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestContextualizer_Prefilter(t *testing.T) {
+	texts := []string{
+		"Plain prose without any indicators at all, just words and more words.",
+		"Hash d41d8cd98f00b204e9800998ecf8427e from 8.8.8.8:443 via http://evil.com/x and bad@evil.org",
+		`Dropped C:\Temp\x.exe and \\fs01\share\y, eth 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed`,
+	}
+	filtered := NewContextualizer()
+	unfiltered := NewContextualizer()
+	unfiltered.DisablePrefilter = true
+	for _, c := range []*Contextualizer{filtered, unfiltered} {
+		if err := c.EnableProfile("secrets"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, text := range texts {
+		for kind, regex := range filtered.Expressions {
+			got := filtered.GetMatches(text, kind, regex)
+			want := unfiltered.GetMatches(text, kind, unfiltered.Expressions[kind])
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s on %q: prefiltered %v, unfiltered %v", kind, text, got, want)
+			}
+		}
+	}
+}
+
+func TestContextualizer_PrefilterSkipsCustomExpressions(t *testing.T) {
+	// A custom email expression without '@' must not inherit the built-in
+	// prefilter of the kind it replaces.
+	c := NewContextualizer(WithExpressions(map[string]*regexp.Regexp{
+		"email": regexp.MustCompile(`\b([a-z]+ at [a-z]+ dot com)\b`),
+	}))
+	if got := c.ExtractAll("write to bob at example dot com")["email"]; len(got) != 1 {
+		t.Errorf("custom email expression was skipped: %v", got)
+	}
+}
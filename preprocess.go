@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"mime/quotedprintable"
+)
+
+// Preprocessor rewrites the input of an extraction before it is scanned,
+// for example to decode a transfer encoding. Preprocessors run in the
+// order they were added, each on the output of the previous one, and
+// before the built-in rewrites (StripHTML, DecodeEscapes, MatchDefanged).
+// Since a preprocessor may change the text arbitrarily, match offsets,
+// contexts and line numbers refer to the preprocessed text.
+type Preprocessor func(text []byte) []byte
+
+// QuotedPrintable decodes quoted-printable text, as found in email bodies.
+// Input that is not valid quoted-printable is returned unchanged.
+func QuotedPrintable(text []byte) []byte {
+	decoded, err := io.ReadAll(quotedprintable.NewReader(bytes.NewReader(text)))
+	if err != nil {
+		return text
+	}
+	return decoded
+}
+
+// Gunzip decompresses gzip data. Input that is not gzip data is returned
+// unchanged.
+func Gunzip(text []byte) []byte {
+	zr, err := gzip.NewReader(bytes.NewReader(text))
+	if err != nil {
+		return text
+	}
+	decoded, err := io.ReadAll(zr)
+	if err != nil {
+		return text
+	}
+	return decoded
+}
+
+// rewrite is a built-in preprocessing step that keeps track of offsets:
+// it returns the rewritten text along with the offset in its input of
+// each byte, plus one trailing entry, or nil when nothing changed.
+type rewrite func(text string) (string, []int)
+
+// rewrites returns the built-in rewrites enabled on c, in the order they
+// apply.
+func (c *Contextualizer) rewrites() []rewrite {
+	var steps []rewrite
+	if c.StripHTML {
+		steps = append(steps, stripHTML)
+	}
+	if c.DecodeEscapes {
+		steps = append(steps, decodeEscapes)
+	}
+	if c.MatchDefanged {
+		steps = append(steps, refang)
+	}
+	return steps
+}
+
+// preprocess runs c.Preprocessors over text.
+func (c *Contextualizer) preprocess(text string) string {
+	if len(c.Preprocessors) == 0 {
+		return text
+	}
+	b := []byte(text)
+	for _, p := range c.Preprocessors {
+		b = p(b)
+	}
+	return string(b)
+}
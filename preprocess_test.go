@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+)
+
+func TestContextualizer_Preprocessors(t *testing.T) {
+	rot13 := func(text []byte) []byte {
+		return bytes.Map(func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z':
+				return 'a' + (r-'a'+13)%26
+			case r >= 'A' && r <= 'Z':
+				return 'A' + (r-'A'+13)%26
+			}
+			return r
+		}, text)
+	}
+
+	c := NewContextualizer(WithPreprocessors(QuotedPrintable, rot13))
+	got := c.ExtractAll("uggc://ri=\r\nvy.rknzcyr/k")
+	if u := got["url"]; len(u) != 1 || u[0].Value != "http://evil.example/x" {
+		t.Errorf("url = %+v", u)
+	}
+}
+
+func TestGunzip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte("beacon 10.1.2.3"))
+	zw.Close()
+
+	if got := string(Gunzip(buf.Bytes())); got != "beacon 10.1.2.3" {
+		t.Errorf("Gunzip() = %q", got)
+	}
+	if got := string(Gunzip([]byte("plain text"))); got != "plain text" {
+		t.Errorf("Gunzip(plain) = %q", got)
+	}
+}
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// profiles are opt-in groups of expressions. They are kept out of the
+// default set because enabling them changes what the output may contain,
+// e.g. live credentials.
+var profiles = map[string]map[string]*regexp.Regexp{
+	"secrets": {
+		"aws_access_key":          regexp.MustCompile(`\b((?:AKIA|ASIA|AGPA|AIDA|AROA|AIPA|ANPA|ANVA)[A-Z2-7]{16})\b`),
+		"aws_secret_key":          regexp.MustCompile(`(?i)aws.{0,20}?(?:secret|private).{0,20}?[\s'"=:]([A-Za-z0-9/+]{40})(?:[^A-Za-z0-9/+=]|$)`),
+		"gcp_api_key":             regexp.MustCompile(`\b(AIza[\w-]{35})(?:[^\w-]|$)`),
+		"github_token":            regexp.MustCompile(`\b((?:ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36}|github_pat_\w{82})\b`),
+		"gitlab_token":            regexp.MustCompile(`\b(glpat-[\w-]{20})(?:[^\w-]|$)`),
+		"slack_token":             regexp.MustCompile(`\b(xox[baprs]-(?:\d+-)+[A-Za-z0-9]+)\b`),
+		"slack_webhook":           regexp.MustCompile(`(https://hooks\.slack\.com/services/T[A-Z0-9]+/B[A-Z0-9]+/[A-Za-z0-9]+)`),
+		"discord_webhook":         regexp.MustCompile(`(https://(?:ptb\.|canary\.)?discord(?:app)?\.com/api/webhooks/\d+/[\w-]+)`),
+		"pem":                     regexp.MustCompile(`((?s)-----BEGIN [A-Z0-9 ]+-----.*?-----END [A-Z0-9 ]+-----)`),
+		"azure_connection_string": regexp.MustCompile(`((?:DefaultEndpointsProtocol=https?;AccountName=[^;\s]+;AccountKey=|Endpoint=sb://[^;\s]+;SharedAccessKeyName=[^;\s]+;SharedAccessKey=)[A-Za-z0-9+/=]{20,}(?:;[A-Za-z]+=[^;\s]+)*)`),
+	},
+	"pii": {
+		"credit_card": regexp.MustCompile(`\b((?:\d[ -]?){12,18}\d)\b`),
+		"iban":        regexp.MustCompile(`\b([A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,4})?)\b`),
+		"ssn":         regexp.MustCompile(`\b(\d{3}-\d{2}-\d{4}|\d{3} \d{2} \d{4})\b`),
+	},
+	"timestamps": {
+		// RFC 3339 and its space-separated log variant, syslog's
+		// "Jan  2 15:04:05", and epoch seconds or milliseconds after a
+		// time-like field name, since bare numbers are everywhere.
+		"timestamp": regexp.MustCompile(`(?i)\b(\d{4}-\d{2}-\d{2}[t ]\d{2}:\d{2}:\d{2}(?:\.\d{1,9})?(?:z|[+-]\d{2}:\d{2})?)(?:[^\w:.+-]|$)|\b((?:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec) [ \d]\d \d{2}:\d{2}:\d{2})\b|\b(?:epoch|time(?:stamp)?|ts|date|created(?:_at)?|updated(?:_at)?)["']?\s*[:=]\s*["']?(1\d{9}(?:\d{3})?)\b`),
+	},
+	"obfuscation": {
+		// Only in a URL host or after an IP-ish keyword, since bare
+		// numbers are everywhere.
+		"obfuscated_ipv4": regexp.MustCompile(`(?i)(?:://(?:[^\s/@]*@)?|\b(?:ip|host|addr|address|connect|c2|server)\b[\s:='"]{1,4})((?:(?:0x[\da-f]{1,2}|0[0-7]{1,3}|\d{1,3})\.){3}(?:0x[\da-f]{1,2}|0[0-7]{1,3}|\d{1,3})|0x[\da-f]{1,8}|0[0-7]{8,11}|\d{8,10})\b`),
+	},
+}
+
+func isProfileKind(kind string) bool {
+	for _, exprs := range profiles {
+		if _, ok := exprs[kind]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// EnableProfile adds the expressions of the named profile to the
+// Contextualizer, replacing any existing expressions of the same kind.
+func (c *Contextualizer) EnableProfile(name string) error {
+	exprs, ok := profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile %q", name)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for kind, regex := range exprs {
+		c.Expressions[kind] = regex
+		c.changeExpression(kind, regex)
+	}
+	c.attachPrefilters(exprs)
+	return nil
+}
@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Query selects sightings from a Store, e.g. the domains first seen this
+// week:
+//
+//	Query{Kind: "domain", Since: weekStart, New: true}.Run(store)
+//
+// Zero fields don't filter.
+type Query struct {
+	Kind string
+	// Since and Until bound a time range, Until excluded. A sighting is in
+	// the range when it was seen during it, or with New, when it was first
+	// seen during it.
+	Since time.Time
+	Until time.Time
+	New   bool
+	// Document keeps the sightings found in the document with this ID.
+	Document string
+	// Value keeps the sightings whose value contains it, ignoring case. A
+	// Value with * or ? is a glob matching the whole value instead, where
+	// * matches any run of characters, slashes included.
+	Value string
+	// Limit caps the number of sightings returned.
+	Limit int
+}
+
+// Run returns the sightings of s selected by q, sorted by type and value.
+func (q Query) Run(s Store) ([]Sighting, error) {
+	out, err := q.selectFrom(s)
+	if err != nil {
+		return nil, err
+	}
+	return q.limit(out), nil
+}
+
+// Top returns the n sightings selected by q with the most hits, ties going
+// to the indicator found in more documents, then to the one seen last. A
+// Limit in q is ignored.
+func (q Query) Top(s Store, n int) ([]Sighting, error) {
+	out, err := q.selectFrom(s)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(out, func(a, b Sighting) int {
+		return cmp.Or(
+			cmp.Compare(b.Hits, a.Hits),
+			cmp.Compare(len(b.Documents), len(a.Documents)),
+			b.LastSeen.Compare(a.LastSeen),
+		)
+	})
+	q.Limit = n
+	return q.limit(out), nil
+}
+
+func (q Query) selectFrom(s Store) ([]Sighting, error) {
+	sightings, err := s.Sightings(q.Kind)
+	if err != nil {
+		return nil, err
+	}
+	match := q.valueMatcher()
+	var out []Sighting
+	for _, si := range sightings {
+		if q.inRange(si) && (q.Document == "" || slices.Contains(si.Documents, q.Document)) && match(si.Value) {
+			out = append(out, si)
+		}
+	}
+	return out, nil
+}
+
+func (q Query) inRange(si Sighting) bool {
+	first := si.FirstSeen
+	last := si.LastSeen
+	if q.New {
+		last = first
+	}
+	if !q.Since.IsZero() && last.Before(q.Since) {
+		return false
+	}
+	return q.Until.IsZero() || first.Before(q.Until)
+}
+
+func (q Query) valueMatcher() func(string) bool {
+	if q.Value == "" {
+		return func(string) bool { return true }
+	}
+	pattern := strings.ToLower(q.Value)
+	if !strings.ContainsAny(pattern, "*?") {
+		return func(val string) bool {
+			return strings.Contains(strings.ToLower(val), pattern)
+		}
+	}
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.NewReplacer(`\*`, `.*`, `\?`, `.`).Replace(expr)
+	regex := regexp.MustCompile(`(?s)^` + expr + `$`)
+	return func(val string) bool {
+		return regex.MatchString(strings.ToLower(val))
+	}
+}
+
+func (q Query) limit(out []Sighting) []Sighting {
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[:q.Limit]
+	}
+	return out
+}
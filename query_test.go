@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuery(t *testing.T) {
+	week := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	s := NewMemoryIndicatorStore()
+	record := func(doc string, at time.Time, matches ...Match) {
+		t.Helper()
+		if err := s.Record(doc, at, matches); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := Match{Type: "domain", Value: "old.example"}
+	fresh := Match{Type: "domain", Value: "login-paypal.example"}
+	login := Match{Type: "url", Value: "https://login-paypal.example/Signin"}
+	record("a", week.AddDate(0, 0, -10), old)
+	record("b", week.AddDate(0, 0, 1), old, fresh, login)
+	record("c", week.AddDate(0, 0, 2), fresh)
+	record("c", week.AddDate(0, 0, 3), fresh)
+
+	values := func(q Query) []string {
+		t.Helper()
+		got, err := q.Run(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, si := range got {
+			out = append(out, si.Value)
+		}
+		return out
+	}
+	tests := []struct {
+		name string
+		q    Query
+		want []string
+	}{
+		{"kind", Query{Kind: "domain"}, []string{"login-paypal.example", "old.example"}},
+		{"seen this week", Query{Kind: "domain", Since: week}, []string{"login-paypal.example", "old.example"}},
+		{"new this week", Query{Kind: "domain", Since: week, New: true}, []string{"login-paypal.example"}},
+		{"before the week", Query{Until: week}, []string{"old.example"}},
+		{"document", Query{Document: "c"}, []string{"login-paypal.example"}},
+		{"substring", Query{Value: "PAYPAL"}, []string{"login-paypal.example", "https://login-paypal.example/Signin"}},
+		{"glob", Query{Value: "https://*/signin"}, []string{"https://login-paypal.example/Signin"}},
+		{"glob anchored", Query{Value: "*.exam?"}, nil},
+		{"limit", Query{Limit: 1}, []string{"login-paypal.example"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := values(tt.q)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Run() = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Run() = %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+
+	top, err := Query{}.Top(s, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != 2 || top[0].Value != "login-paypal.example" || top[0].Hits != 3 || top[1].Value != "old.example" {
+		t.Errorf("Top() = %+v", top)
+	}
+}
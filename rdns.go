@@ -0,0 +1,137 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rdnsCacheSize bounds the number of PTR lookups a ReverseDNSEnricher
+// remembers.
+const rdnsCacheSize = 4096
+
+// AddrResolver resolves addresses to host names. *net.Resolver satisfies
+// it.
+type AddrResolver interface {
+	LookupAddr(ctx context.Context, addr string) ([]string, error)
+}
+
+// ReverseDNSEnricher is an Enricher that looks up the PTR record of public
+// ipv4, ipv6 and ipport matches. It attaches the first name as "rdns.ptr"
+// metadata and emits it as a related domain match. Private, loopback and
+// link-local addresses are never looked up.
+//
+// Answers, including the absence of one, are cached, and lookups are
+// spaced at least Interval apart so a large result doesn't flood the
+// resolver.
+type ReverseDNSEnricher struct {
+	Resolver AddrResolver
+	Interval time.Duration
+
+	cache *lru[string] // PTR names by address, "" for none
+	mu    sync.Mutex
+	next  time.Time
+}
+
+// NewReverseDNSEnricher returns a ReverseDNSEnricher using r that performs
+// at most one lookup per interval. An interval of zero disables rate
+// limiting.
+func NewReverseDNSEnricher(r AddrResolver, interval time.Duration) *ReverseDNSEnricher {
+	return &ReverseDNSEnricher{Resolver: r, Interval: interval, cache: newLRU[string](rdnsCacheSize)}
+}
+
+// Name implements Enricher.
+func (*ReverseDNSEnricher) Name() string { return "rdns" }
+
+// Enrich implements Enricher.
+func (d *ReverseDNSEnricher) Enrich(ctx context.Context, m Match) (Enrichment, error) {
+	var addr netip.Addr
+	switch m.Type {
+	case "ipv4", "ipv6":
+		a, err := netip.ParseAddr(Refang(m.Value))
+		if err != nil {
+			return Enrichment{}, nil
+		}
+		addr = a
+	case "ipport":
+		ap, err := netip.ParseAddrPort(Refang(m.Value))
+		if err != nil {
+			return Enrichment{}, nil
+		}
+		addr = ap.Addr()
+	default:
+		return Enrichment{}, nil
+	}
+	addr = addr.WithZone("").Unmap()
+	if !addr.IsGlobalUnicast() || isPrivateIP(addr) {
+		return Enrichment{}, nil
+	}
+
+	name, err := d.lookup(ctx, addr.String())
+	if err != nil || name == "" {
+		return Enrichment{}, err
+	}
+	return Enrichment{
+		Metadata: map[string]string{"ptr": name},
+		Related:  []Match{{Value: name, Type: "domain"}},
+	}, nil
+}
+
+// lookup returns the PTR name of addr, or "" if it has none.
+func (d *ReverseDNSEnricher) lookup(ctx context.Context, addr string) (string, error) {
+	if d.cache != nil {
+		if name, ok := d.cache.get(addr); ok {
+			return name, nil
+		}
+	}
+	if err := d.wait(ctx); err != nil {
+		return "", err
+	}
+	names, err := d.Resolver.LookupAddr(ctx, addr)
+	if dnsErr := (*net.DNSError)(nil); errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		names, err = nil, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	var name string
+	if len(names) > 0 {
+		name = strings.ToLower(strings.TrimSuffix(names[0], "."))
+	}
+	if d.cache != nil {
+		d.cache.put(addr, name)
+	}
+	return name, nil
+}
+
+// wait blocks until the next lookup slot, or until ctx is done.
+func (d *ReverseDNSEnricher) wait(ctx context.Context) error {
+	if d.Interval <= 0 {
+		return nil
+	}
+	d.mu.Lock()
+	now := time.Now()
+	slot := d.next
+	if slot.Before(now) {
+		slot = now
+	}
+	d.next = slot.Add(d.Interval)
+	d.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
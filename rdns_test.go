@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+type fakeAddrResolver struct {
+	mu    sync.Mutex
+	names map[string][]string
+	calls int
+}
+
+func (f *fakeAddrResolver) LookupAddr(_ context.Context, addr string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	return f.names[addr], nil
+}
+
+func TestReverseDNSEnricher(t *testing.T) {
+	resolver := &fakeAddrResolver{names: map[string][]string{"192.0.2.10": {"Mail.Example.COM."}}}
+	rdns := NewReverseDNSEnricher(resolver, 0)
+	c := NewContextualizer(WithEnricher(rdns, 0))
+	r := NewResult()
+	c.ExtractInto("beacon to 192.0.2.10", r)
+
+	if err := c.Enrich(context.Background(), r); err != nil {
+		t.Fatalf("Enrich() error = %v", err)
+	}
+	if got := r.Matches["ipv4"][0].Metadata["rdns.ptr"]; got != "mail.example.com" {
+		t.Errorf("rdns.ptr = %q, want mail.example.com", got)
+	}
+	if d := r.Matches["domain"]; len(d) != 1 || d[0].Value != "mail.example.com" || d[0].Parent != "192.0.2.10" {
+		t.Errorf("domain = %+v", d)
+	}
+
+	// A repeat is served from the cache; private addresses are never
+	// looked up.
+	rdns.Enrich(context.Background(), Match{Value: "192.0.2.10", Type: "ipv4"})
+	rdns.Enrich(context.Background(), Match{Value: "10.1.2.3", Type: "ipv4"})
+	if resolver.calls != 1 {
+		t.Errorf("lookups = %d, want 1", resolver.calls)
+	}
+}
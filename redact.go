@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Redact replaces every indicator of the given kinds in text, or of all
+// enabled kinds when none are given, with a placeholder such as
+// "[EMAIL_1]" or "[IPV4_2]". Occurrences of the same value, compared in
+// normalized form (see Normalize), share a placeholder. It returns the
+// redacted text and one match per placeholder, at its first occurrence,
+// with the placeholder in Metadata["placeholder"], so the mapping can be
+// kept to reverse it.
+//
+// Unknown and disabled kinds are skipped. Matches derived from others,
+// such as base_domain, are covered by their parent's placeholder. With
+// Preprocessors set, the redacted text is their output, since that is
+// what match offsets refer to.
+func (c *Contextualizer) Redact(text string, kinds ...string) (string, []Match) {
+	counts := make(map[string]int)
+	return c.redact(text, kinds, func(m Match) string {
+		counts[m.Type]++
+		return "[" + strings.ToUpper(m.Type) + "_" + strconv.Itoa(counts[m.Type]) + "]"
+	})
+}
+
+// redact is Redact with placeholders chosen by placeholder, which is
+// called once per distinct value.
+func (c *Contextualizer) redact(text string, kinds []string, placeholder func(Match) string) (string, []Match) {
+	found := c.spans(text, kinds)
+	base := c.preprocess(text)
+	var b strings.Builder
+	var mapping []Match
+	placeholders := make(map[string]string)
+	last := 0
+	for _, m := range found {
+		key := m.Type + "\x00" + Normalize(m.Type, m.Value)
+		ph, ok := placeholders[key]
+		if !ok {
+			ph = placeholder(m)
+			placeholders[key] = ph
+			if m.Metadata == nil {
+				m.Metadata = make(map[string]string, 1)
+			}
+			m.Metadata["placeholder"] = ph
+			mapping = append(mapping, m)
+		}
+		b.WriteString(base[last:m.Start])
+		b.WriteString(ph)
+		last = m.End
+	}
+	b.WriteString(base[last:])
+	return b.String(), mapping
+}
+
+// spans returns every occurrence of an indicator of the given kinds in
+// text, or of all enabled kinds when none are given, in order and without
+// overlaps: earlier and then longer spans win. Derived matches are left
+// out, since they share their parent's span.
+func (c *Contextualizer) spans(text string, kinds []string) []Match {
+	exprs := c.expressions()
+	entropy := c.Entropy
+	if len(kinds) > 0 {
+		all := exprs
+		exprs = make(map[string]*regexp.Regexp, len(kinds))
+		entropy = nil
+		for _, kind := range kinds {
+			if regex, ok := all[kind]; ok {
+				exprs[kind] = regex
+			} else if kind == "secret_candidate" {
+				entropy = c.Entropy
+			}
+		}
+	}
+
+	var found []Match
+	c.scan(text, exprs, entropy, NoDedup, func(m Match) bool {
+		if m.Parent == "" {
+			found = append(found, m)
+		}
+		return true
+	})
+	slices.SortStableFunc(found, func(a, b Match) int {
+		if a.Start != b.Start {
+			return a.Start - b.Start
+		}
+		return b.End - a.End
+	})
+	out := found[:0]
+	last := 0
+	for _, m := range found {
+		if m.Start >= last {
+			out = append(out, m)
+			last = m.End
+		}
+	}
+	return out
+}
@@ -0,0 +1,27 @@
+package parser
+
+import "testing"
+
+func TestContextualizer_Redact(t *testing.T) {
+	c := NewContextualizer()
+	text := "alice@corp.example logged in from 192.0.2.10, then 198.51.100.4; alice@corp.example again from 192.0.2.10"
+
+	got, mapping := c.Redact(text)
+	want := "[EMAIL_1] logged in from [IPV4_1], then [IPV4_2]; [EMAIL_1] again from [IPV4_1]"
+	if got != want {
+		t.Errorf("Redact() = %q, want %q", got, want)
+	}
+	if len(mapping) != 3 {
+		t.Fatalf("mapping = %v", mapping)
+	}
+	for _, m := range mapping {
+		if text[m.Start:m.End] != m.Value || m.Metadata["placeholder"] == "" {
+			t.Errorf("mapping entry %+v", m)
+		}
+	}
+
+	got, _ = c.Redact(text, "ipv4")
+	if want := "alice@corp.example logged in from [IPV4_1], then [IPV4_2]; alice@corp.example again from [IPV4_1]"; got != want {
+		t.Errorf("Redact(ipv4) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+)
+
+// ErrNoConfigFile is returned by Reload and Watch for a Contextualizer that
+// was not created by LoadConfig.
+var ErrNoConfigFile = errors.New("parser: no config file to reload")
+
+// checks returns the current PrivateChecks. Reload swaps them as a whole,
+// so extractions read them through here rather than c.Checks.
+func (c *Contextualizer) checks() *PrivateChecks {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Checks
+}
+
+// Reload re-reads the config file c was loaded from and swaps in its
+// expressions, ignore lists, classifications, source tags and TLP marking,
+// so long-running services pick up changes without a restart. Entries
+// added since by the Load* methods and AddIgnorePattern are kept, as is a
+// HashAllowlist the file doesn't replace, and the expressions and kinds
+// changed since by SetExpression, RemoveExpression, EnableProfile,
+// RegisterExtractor, DisableKind and EnableKind are applied on top of the
+// file. Extractions already running
+// finish with the old configuration. If the file cannot be loaded, c is
+// left unchanged.
+func (c *Contextualizer) Reload() error {
+	if c.configPath == "" {
+		return ErrNoConfigFile
+	}
+	fresh, err := LoadConfig(c.configPath, c.configOpts...)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	checks := fresh.Checks
+	if c.added != nil {
+		checks.merge(c.added)
+	}
+	if checks.HashAllowlist == nil {
+		checks.HashAllowlist = c.Checks.HashAllowlist
+	}
+	c.disabled = c.replayChanges(fresh.Expressions, fresh.prefilters, fresh.disabled)
+	c.Expressions = fresh.Expressions
+	c.prefilters = fresh.prefilters
+	c.Checks = checks
+	c.Classifications = cloneClassifications(fresh.Classifications)
+	c.SourceTags = cloneSourceTags(fresh.SourceTags)
+	c.TLP = fresh.TLP
+	c.configInfo = fresh.configInfo
+	return nil
+}
+
+// Watch polls the config file c was loaded from every interval and calls
+// Reload whenever its size or modification time differs from the loaded
+// version. Errors are passed
+// to onError, which may be nil. Watch blocks until ctx is done and returns
+// its error.
+func (c *Contextualizer) Watch(ctx context.Context, interval time.Duration, onError func(error)) error {
+	if c.configPath == "" {
+		return ErrNoConfigFile
+	}
+	report := func(err error) {
+		if onError != nil {
+			onError(err)
+		}
+	}
+
+	var failed os.FileInfo
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		fi, err := os.Stat(c.configPath)
+		if err != nil {
+			report(err)
+			continue
+		}
+		c.mu.RLock()
+		last := c.configInfo
+		c.mu.RUnlock()
+		if sameFile(fi, last) || sameFile(fi, failed) {
+			continue
+		}
+		if err := c.Reload(); err != nil {
+			// Report a broken file once, not on every tick.
+			failed = fi
+			report(err)
+		}
+	}
+}
+
+func sameFile(a, b os.FileInfo) bool {
+	return a != nil && b != nil && a.Size() == b.Size() && a.ModTime().Equal(b.ModTime())
+}
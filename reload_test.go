@@ -0,0 +1,257 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContextualizer_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "parser.json")
+	write := func(data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"ignored_domains": ["corp.example"]}`)
+
+	c, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := "beacon to evil.com and corp.example"
+	if got := c.ExtractAll(text)["domain"]; len(got) != 1 || got[0].Value != "evil.com" {
+		t.Fatalf("domain = %v", got)
+	}
+
+	write(`{"ignored_domains": ["corp.example", "evil.com"], "expressions": {"ticket": "\\b(INC\\d{6})\\b"}}`)
+	if err := c.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	got := c.ExtractAll(text + " INC123456")
+	if got["domain"] != nil || len(got["ticket"]) != 1 {
+		t.Errorf("after reload got %v", got)
+	}
+
+	write(`{"expressions": {"bad": "("}}`)
+	if err := c.Reload(); err == nil {
+		t.Error("Reload of a bad config succeeded")
+	}
+	if got := c.ExtractAll(text)["domain"]; got != nil {
+		t.Errorf("failed reload changed the configuration: %v", got)
+	}
+
+	if err := NewContextualizer().Reload(); !errors.Is(err, ErrNoConfigFile) {
+		t.Errorf("Reload() without a file = %v", err)
+	}
+}
+
+func TestContextualizer_ReloadKeepsLoadedEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "parser.json")
+	if err := os.WriteFile(path, []byte(`{"ignored_domains": ["corp.example"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.LoadIgnoredDomains(strings.NewReader("evil.com\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddIgnorePattern("url", `/healthz`); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"ignored_domains": ["other.example"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	got := c.ExtractAll("evil.com corp.example other.example https://bad.example/healthz")
+	if d := values(got["domain"]); !slices.Equal(d, []string{"domain:corp.example", "domain:bad.example"}) {
+		t.Errorf("domain = %v, want [domain:corp.example domain:bad.example]", d)
+	}
+	if got["url"] != nil {
+		t.Errorf("url = %v, want the ignore pattern kept", got["url"])
+	}
+
+	// ApplyConfig replaces the checks as a whole, so the next Reload goes
+	// back to the file.
+	if err := c.ApplyConfig(c.Config()); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if d := c.ExtractAll("evil.com")["domain"]; d == nil {
+		t.Error("evil.com still ignored after ApplyConfig and Reload")
+	}
+}
+
+func TestContextualizer_ReloadKeepsExpressionChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "parser.json")
+	if err := os.WriteFile(path, []byte(`{"expressions": {"ticket": "\\b(INC\\d{6})\\b"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddExpression("case_id", `\b(CASE-\d{4})\b`); err != nil {
+		t.Fatal(err)
+	}
+	c.RemoveExpression("ticket")
+	c.DisableKind("md5")
+	if err := c.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := c.ExtractAll("INC123456 CASE-0042 d41d8cd98f00b204e9800998ecf8427e")
+	if got["ticket"] != nil || len(got["case_id"]) != 1 || got["md5"] != nil {
+		t.Errorf("after reload got %v", got)
+	}
+
+	c.EnableKind("md5")
+	if err := c.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if !c.KindEnabled("md5") {
+		t.Error("md5 disabled again by Reload")
+	}
+}
+
+func TestContextualizer_ReloadConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "parser.json")
+	if err := os.WriteFile(path, []byte(`{"tlp": "TLP:GREEN"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := c.Config()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 20 {
+			if err := c.Reload(); err != nil {
+				t.Error(err)
+				return
+			}
+			c.Config()
+		}
+	}()
+	for range 20 {
+		c.ExtractAll("evil.com 8.8.8.8")
+		if _, err := c.LoadIgnoredDomains(strings.NewReader("corp.example\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-done
+	if err := c.ApplyConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestContextualizer_ReloadTLP(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "parser.json")
+	if err := os.WriteFile(path, []byte(`{"tlp": "TLP:GREEN"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"tlp": "TLP:RED"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.ExtractAll("evil.com")["domain"]; len(got) != 1 || got[0].TLP != TLPRed {
+		t.Errorf("domain = %+v, want TLP RED", got)
+	}
+	if got := c.Config().TLP; got != TLPRed {
+		t.Errorf("Config().TLP = %q", got)
+	}
+}
+
+func TestContextualizer_ReloadClassifications(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "parser.json")
+	if err := os.WriteFile(path, []byte(`{"classifications": {"domain": {"severity": "info"}}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"classifications": {"domain": {"severity": "high", "tags": ["network"]}}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	got := c.ExtractAll("evil.com")["domain"]
+	if len(got) != 1 || got[0].Severity != "high" || len(got[0].Tags) != 1 || got[0].Tags[0] != "network" {
+		t.Errorf("domain = %+v, want severity high tagged network", got)
+	}
+}
+
+func TestContextualizer_ReloadSourceTags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "parser.json")
+	if err := os.WriteFile(path, []byte(`{"source_tags": {"mail": ["email-gateway"]}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"source_tags": {"mail": ["phishing"]}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	r := &Result{Source: "mail"}
+	c.ExtractInto("evil.com", r)
+	if got := r.Matches["domain"]; len(got) != 1 || len(got[0].Tags) != 1 || got[0].Tags[0] != "phishing" {
+		t.Errorf("domain = %+v, want tagged phishing only", got)
+	}
+}
+
+func TestContextualizer_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "parser.json")
+	if err := os.WriteFile(path, []byte(`{}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- c.Watch(ctx, 5*time.Millisecond, nil) }()
+
+	if err := os.WriteFile(path, []byte(`{"ignored_domains": ["evil.com"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for c.ExtractAll("evil.com")["domain"] != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("Watch did not pick up the change")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Watch() = %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package report
+
+import (
+	"html/template"
+	"io"
+
+	"github.com/rexlx/parser"
+)
+
+var htmlReport = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }
+th { background: #f2f2f2; }
+td.num { text-align: right; }
+code { word-break: break-all; }
+.context { color: #555; font-size: 0.9em; }
+.tlp { display: inline-block; padding: 0.2em 0.6em; background: #000; font-weight: bold; }
+.tlp-CLEAR { color: #fff; }
+.tlp-GREEN { color: #33ff00; }
+.tlp-AMBER, .tlp-AMBER\+STRICT { color: #ffc000; }
+.tlp-RED { color: #ff2b2b; }
+</style>
+</head>
+<body>
+{{with .TLP}}<p class="tlp tlp-{{slice . 4}}">{{.}}</p>
+{{end}}<h1>{{.Title}}</h1>
+{{if .Generated}}<p>Generated {{.Generated}}</p>
+{{end}}<p>{{.Total}} matches{{if .Suppressed}}, {{.Suppressed}} suppressed by ignore lists{{end}}.</p>
+{{if .Sections}}<table>
+<tr><th>Kind</th><th>Matches</th><th>Unique</th><th>Suppressed</th></tr>
+{{range .Sections}}<tr><td><a href="#kind-{{.Kind}}">{{.Kind}}</a></td><td class="num">{{.Count}}</td><td class="num">{{.Unique}}</td><td class="num">{{.Suppressed}}</td></tr>
+{{end}}</table>
+{{range .Sections}}{{if .Rows}}<h2 id="kind-{{.Kind}}">{{.Kind}}</h2>
+<table>
+<tr><th>Value</th><th>Location</th><th>Confidence</th><th>Context</th></tr>
+{{range .Rows}}<tr><td><code>{{.Value}}</code></td><td>{{.Location}}</td><td class="num">{{.Confidence}}</td><td class="context">{{.Context}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}{{end}}</body>
+</html>
+`))
+
+// HTML writes r to w as a standalone HTML page.
+func HTML(w io.Writer, r *parser.Result, opts Options) error {
+	return htmlReport.Execute(w, build(r, opts))
+}
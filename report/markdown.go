@@ -0,0 +1,62 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rexlx/parser"
+)
+
+// Markdown writes r to w as a Markdown report.
+func Markdown(w io.Writer, r *parser.Result, opts Options) error {
+	p := build(r, opts)
+	bw := bufio.NewWriter(w)
+
+	if p.TLP != "" {
+		fmt.Fprintf(bw, "**%s**\n\n", p.TLP)
+	}
+	fmt.Fprintf(bw, "# %s\n\n", mdText(p.Title))
+	if p.Generated != "" {
+		fmt.Fprintf(bw, "Generated %s\n\n", p.Generated)
+	}
+	fmt.Fprintf(bw, "%d matches", p.Total)
+	if p.Suppressed > 0 {
+		fmt.Fprintf(bw, ", %d suppressed by ignore lists", p.Suppressed)
+	}
+	bw.WriteString(".\n\n")
+	if len(p.Sections) == 0 {
+		return bw.Flush()
+	}
+
+	bw.WriteString("| Kind | Matches | Unique | Suppressed |\n|---|---:|---:|---:|\n")
+	for _, s := range p.Sections {
+		fmt.Fprintf(bw, "| %s | %d | %d | %d |\n", mdText(s.Kind), s.Count, s.Unique, s.Suppressed)
+	}
+	for _, s := range p.Sections {
+		if len(s.Rows) == 0 {
+			continue
+		}
+		fmt.Fprintf(bw, "\n## %s\n\n| Value | Location | Confidence | Context |\n|---|---|---:|---|\n", mdText(s.Kind))
+		for _, row := range s.Rows {
+			fmt.Fprintf(bw, "| %s | %s | %s | %s |\n", mdCode(row.Value), row.Location, row.Confidence, mdText(row.Context))
+		}
+	}
+	return bw.Flush()
+}
+
+// mdText makes s safe for a Markdown table cell.
+func mdText(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.NewReplacer(`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`", "<", "&lt;", "[", `\[`).Replace(s)
+}
+
+// mdCode formats a value as a code span in a table cell, falling back to
+// escaped text for values containing backticks.
+func mdCode(s string) string {
+	if strings.ContainsAny(s, "`\n") {
+		return mdText(s)
+	}
+	return "`" + strings.ReplaceAll(s, "|", `\|`) + "`"
+}
@@ -0,0 +1,105 @@
+// Package report renders the matches of a parser.Result as a standalone
+// HTML or Markdown document, with per-kind counts and tables, for
+// attaching to incident tickets. A marked Result (see
+// parser.Result.Marking) gets its TLP at the top of the document.
+package report
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/rexlx/parser"
+)
+
+// Options adjust a report.
+type Options struct {
+	// Title heads the report; it defaults to "Indicator report".
+	Title string
+	// Generated, when not zero, is printed under the title.
+	Generated time.Time
+	// Defang defangs every value (see parser.Defang), so the report can be
+	// opened without producing clickable indicators.
+	Defang bool
+}
+
+// page is what both renderers print.
+type page struct {
+	TLP        string
+	Title      string
+	Generated  string
+	Total      int
+	Suppressed int
+	Sections   []section
+}
+
+type section struct {
+	Kind       string
+	Count      int
+	Unique     int
+	Suppressed int
+	Rows       []row
+}
+
+type row struct {
+	Value      string
+	Location   string
+	Confidence string
+	Context    string
+}
+
+func build(r *parser.Result, opts Options) page {
+	p := page{Title: cmp.Or(opts.Title, "Indicator report")}
+	if !opts.Generated.IsZero() {
+		p.Generated = opts.Generated.UTC().Format(time.RFC3339)
+	}
+	summary := r.Summary(0)
+	p.Total = summary.Total
+	p.TLP = summary.TLP.String()
+
+	kinds := slices.Collect(maps.Keys(r.Matches))
+	for kind := range summary.Suppressed {
+		if _, ok := r.Matches[kind]; !ok {
+			kinds = append(kinds, kind)
+		}
+	}
+	slices.Sort(kinds)
+	for _, kind := range kinds {
+		matches := slices.Clone(r.Matches[kind])
+		s := section{
+			Kind:       kind,
+			Count:      len(matches),
+			Unique:     summary.Unique[kind],
+			Suppressed: summary.Suppressed[kind],
+		}
+		p.Suppressed += s.Suppressed
+		if s.Count == 0 && s.Suppressed == 0 {
+			continue
+		}
+		slices.SortStableFunc(matches, func(a, b parser.Match) int { return cmp.Compare(a.Start, b.Start) })
+		for _, m := range matches {
+			s.Rows = append(s.Rows, newRow(m, opts))
+		}
+		p.Sections = append(p.Sections, s)
+	}
+	return p
+}
+
+func newRow(m parser.Match, opts Options) row {
+	val := m.Value
+	if opts.Defang {
+		val = parser.Defang(val)
+	}
+	loc := fmt.Sprintf("%d-%d", m.Start, m.End)
+	if m.Line > 0 {
+		loc = fmt.Sprintf("%d:%d", m.Line, m.Column)
+	}
+	var conf string
+	if m.Confidence > 0 {
+		conf = strconv.FormatFloat(m.Confidence, 'f', 2, 64)
+	}
+	return row{Value: val, Location: loc, Confidence: conf, Context: m.Context}
+}
@@ -0,0 +1,85 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rexlx/parser"
+)
+
+func testResult() *parser.Result {
+	c := parser.NewContextualizer(parser.WithIgnoredDomains("corp.example"), parser.WithContextWindow(10))
+	r := parser.NewResult()
+	c.ExtractInto("C2 evil.com|x at 198.51.100.4, mail <b>corp.example</b>", r)
+	return r
+}
+
+func TestMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	opts := Options{Title: "Case 42", Generated: time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC), Defang: true}
+	if err := Markdown(&buf, testResult(), opts); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"# Case 42\n",
+		"Generated 2024-03-05T10:00:00Z",
+		", 1 suppressed by ignore lists.",
+		"| domain | 1 | 1 | 1 |",
+		"## ipv4",
+		"| `198[.]51[.]100[.]4` | 17-29 |",
+		`C2 evil.com\|x`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Markdown() missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := HTML(&buf, testResult(), Options{}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"<title>Indicator report</title>",
+		`<h2 id="kind-domain">domain</h2>`,
+		"<td><code>198.51.100.4</code></td>",
+		"&lt;b&gt;",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("HTML() missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "<b>") {
+		t.Error("HTML() did not escape the context")
+	}
+}
+
+func TestTLPMarking(t *testing.T) {
+	r := testResult()
+	r.TLP = parser.TLPAmberStrict
+
+	var md, page bytes.Buffer
+	if err := Markdown(&md, r, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(md.String(), "**TLP:AMBER+STRICT**\n\n# Indicator report") {
+		t.Errorf("Markdown() does not open with the marking:\n%s", md.String())
+	}
+	if err := HTML(&page, r, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(page.String(), `">TLP:AMBER&#43;STRICT</p>`) {
+		t.Errorf("HTML() missing the marking:\n%s", page.String())
+	}
+
+	md.Reset()
+	Markdown(&md, testResult(), Options{})
+	if strings.Contains(md.String(), "TLP:") {
+		t.Errorf("unmarked Markdown() has a marking:\n%s", md.String())
+	}
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"context"
+	"strconv"
+)
+
+// Verdict is a threat-intel source's opinion of an indicator. The zero
+// Verdict means the source knows nothing about it.
+type Verdict struct {
+	// Label is the source's classification, such as "malicious",
+	// "suspicious" or "harmless".
+	Label string
+	// Score is the source's own rating, such as the number of engines
+	// flagging a file or a 0-100 risk score.
+	Score float64
+}
+
+// Reputation looks indicators up in a threat-intel source such as
+// VirusTotal, OTX or MISP. kind is the match kind ("sha256", "domain",
+// "ipv4", ...) and value its refanged, normalized value. A source that
+// doesn't handle kind should return the zero Verdict.
+type Reputation interface {
+	Lookup(ctx context.Context, kind, value string) (Verdict, error)
+}
+
+// ReputationEnricher is an Enricher that attaches a Reputation's verdict
+// to every match it knows about, as "<name>.verdict" and "<name>.score"
+// metadata.
+type ReputationEnricher struct {
+	name   string
+	Source Reputation
+}
+
+// NewReputationEnricher returns a ReputationEnricher for src whose
+// metadata keys are prefixed with name, so several sources can be
+// registered side by side.
+func NewReputationEnricher(name string, src Reputation) *ReputationEnricher {
+	return &ReputationEnricher{name: name, Source: src}
+}
+
+// Name implements Enricher.
+func (r *ReputationEnricher) Name() string { return r.name }
+
+// Enrich implements Enricher.
+func (r *ReputationEnricher) Enrich(ctx context.Context, m Match) (Enrichment, error) {
+	val := m.Normalized
+	if val == "" {
+		val = m.Value
+	}
+	v, err := r.Source.Lookup(ctx, m.Type, Refang(val))
+	if err != nil || v == (Verdict{}) {
+		return Enrichment{}, err
+	}
+	meta := map[string]string{"score": strconv.FormatFloat(v.Score, 'f', -1, 64)}
+	if v.Label != "" {
+		meta["verdict"] = v.Label
+	}
+	return Enrichment{Metadata: meta}, nil
+}
@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type fakeReputation map[string]Verdict
+
+func (f fakeReputation) Lookup(_ context.Context, kind, value string) (Verdict, error) {
+	if kind == "ipv4" {
+		return Verdict{}, errors.New("quota exceeded")
+	}
+	return f[kind+":"+value], nil
+}
+
+func TestReputationEnricher(t *testing.T) {
+	intel := fakeReputation{"domain:evil.example": {Label: "malicious", Score: 87}}
+	c := NewContextualizer(WithDefanged(), WithEnricher(NewReputationEnricher("vt", intel), 0))
+	r := NewResult()
+	c.ExtractInto("evil[.]example, fine.example and 192.0.2.1", r)
+
+	err := c.Enrich(context.Background(), r)
+	if err == nil || !strings.Contains(err.Error(), "quota exceeded") {
+		t.Errorf("Enrich() error = %v, want quota exceeded", err)
+	}
+	var seen bool
+	for _, m := range r.Matches["domain"] {
+		switch Refang(m.Value) {
+		case "evil.example":
+			seen = true
+			if m.Metadata["vt.verdict"] != "malicious" || m.Metadata["vt.score"] != "87" {
+				t.Errorf("evil.example Metadata = %v", m.Metadata)
+			}
+		case "fine.example":
+			if m.Metadata != nil {
+				t.Errorf("fine.example Metadata = %v", m.Metadata)
+			}
+		}
+	}
+	if !seen {
+		t.Errorf("evil.example not extracted: %+v", r.Matches["domain"])
+	}
+}
@@ -0,0 +1,190 @@
+package parser
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// Result holds the matches of an extraction grouped by type. A Result can
+// be reused across extractions with Reset, which keeps the allocated
+// slices, so hot loops don't allocate a fresh map per document.
+type Result struct {
+	Matches map[string][]Match
+	// Truncated reports that Contextualizer.Limits cut the extraction
+	// short, so Matches is incomplete.
+	Truncated bool
+	// Suppressed is the number of values per type dropped by the ignore
+	// lists, counting every occurrence.
+	Suppressed map[string]int
+	// Source identifies the document or shard the Result was extracted
+	// from, and is recorded in Match.Sources by Merge.
+	Source string
+	// TLP, when set, marks the matches extracted into the Result in place
+	// of Contextualizer.TLP. Merge keeps the most restrictive.
+	TLP TLP
+}
+
+// NewResult returns an empty Result.
+func NewResult() *Result {
+	return &Result{Matches: make(map[string][]Match), Suppressed: make(map[string]int)}
+}
+
+// Reset empties the Result while keeping its allocated capacity, its
+// Source and its TLP.
+func (r *Result) Reset() {
+	r.Truncated = false
+	if r.Suppressed == nil {
+		r.Suppressed = make(map[string]int)
+	}
+	clear(r.Suppressed)
+	if r.Matches == nil {
+		r.Matches = make(map[string][]Match)
+		return
+	}
+	for typ, matches := range r.Matches {
+		clear(matches)
+		r.Matches[typ] = matches[:0]
+	}
+}
+
+// Len returns the total number of matches across all types.
+func (r *Result) Len() int {
+	var n int
+	for _, matches := range r.Matches {
+		n += len(matches)
+	}
+	return n
+}
+
+// Ordered returns all matches in a stable order: by position in the
+// document, then by type, then by end offset. Derived matches share the
+// offsets of the match they came from.
+func (r *Result) Ordered() []Match {
+	return orderMatches(r.Matches)
+}
+
+// Merge adds the matches of others to r, keeping one match per type and
+// normalized, case-insensitive value like a single extraction would, so
+// the shards of a large document can be extracted in parallel and merged.
+// Every match lists in Sources the Source of each Result it was found in;
+// a repeated match keeps the offsets of the first one, which refer to its
+// own shard. Truncated and Suppressed are combined.
+func (r *Result) Merge(others ...*Result) {
+	if r.Matches == nil {
+		r.Matches = make(map[string][]Match)
+	}
+	if r.Suppressed == nil {
+		r.Suppressed = make(map[string]int)
+	}
+	index := make(map[string]int)
+	for _, matches := range r.Matches {
+		for i := range matches {
+			index[sessionKey(matches[i])] = i
+			matches[i].Sources = appendUnique(matches[i].Sources, r.Source)
+		}
+	}
+	for _, o := range others {
+		r.Truncated = r.Truncated || o.Truncated
+		r.TLP = stricterTLP(r.TLP, o.TLP)
+		for typ, n := range o.Suppressed {
+			r.Suppressed[typ] += n
+		}
+		for _, m := range o.Ordered() {
+			key := sessionKey(m)
+			if i, ok := index[key]; ok {
+				dup := &r.Matches[m.Type][i]
+				dup.Sources = appendUnique(appendUnique(dup.Sources, m.Sources...), o.Source)
+				continue
+			}
+			m.Sources = appendUnique(slices.Clone(m.Sources), o.Source)
+			index[key] = len(r.Matches[m.Type])
+			r.add(m)
+		}
+	}
+}
+
+// appendUnique appends the non-empty items missing from list. list is
+// clipped first, so a slice shared between matches is never written to.
+func appendUnique(list []string, items ...string) []string {
+	list = slices.Clip(list)
+	for _, item := range items {
+		if item != "" && !slices.Contains(list, item) {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func (r *Result) add(m Match) {
+	r.Matches[m.Type] = append(r.Matches[m.Type], m)
+}
+
+// ExtractInto is ExtractAll writing into r, which is Reset first. The
+// matches carry the SourceTags of r.Source, and r.TLP when it is set.
+func (c *Contextualizer) ExtractInto(text string, r *Result) {
+	c.ExtractIntoFlags(text, r, 0)
+}
+
+// ExtractIntoFlags is ExtractInto adjusted by flags, see ExtractAllFlags.
+func (c *Contextualizer) ExtractIntoFlags(text string, r *Result, flags ScanFlag) {
+	c.extractInto(context.Background(), text, r, flags)
+}
+
+// extractInto is ExtractIntoFlags stopping early once ctx is done.
+func (c *Contextualizer) extractInto(ctx context.Context, text string, r *Result, flags ScanFlag) {
+	r.Reset()
+	stats := &Stats{Suppressed: r.Suppressed}
+	tags := c.sourceTags(r.Source)
+	r.Truncated = c.scanStats(text, c.expressions(), c.Entropy, flags, stats, func(m Match) bool {
+		if r.TLP != "" {
+			m.TLP = r.TLP
+		}
+		if len(tags) > 0 {
+			m.Tags = appendUnique(m.Tags, tags...)
+		}
+		r.add(m)
+		return ctx.Err() == nil
+	})
+}
+
+// ExtractOrdered returns the matches ExtractAll would, as a single slice
+// in the order of Result.Ordered, so output can be diffed between runs.
+func (c *Contextualizer) ExtractOrdered(text string) []Match {
+	return orderMatches(c.ExtractAll(text))
+}
+
+func orderMatches(byType map[string][]Match) []Match {
+	var n int
+	for _, matches := range byType {
+		n += len(matches)
+	}
+	ordered := make([]Match, 0, n)
+	for _, typ := range slices.Sorted(maps.Keys(byType)) {
+		ordered = append(ordered, byType[typ]...)
+	}
+	slices.SortStableFunc(ordered, func(a, b Match) int {
+		return cmp.Or(
+			cmp.Compare(a.Start, b.Start),
+			cmp.Compare(a.Type, b.Type),
+			cmp.Compare(a.End, b.End),
+		)
+	})
+	return ordered
+}
+
+// seenPool recycles the per-kind deduplication sets.
+var seenPool = sync.Pool{
+	New: func() any { return make(map[string]bool) },
+}
+
+func getSeen() map[string]bool {
+	return seenPool.Get().(map[string]bool)
+}
+
+func putSeen(seen map[string]bool) {
+	clear(seen)
+	seenPool.Put(seen)
+}
@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+const benchText = "Hash d41d8cd98f00b204e9800998ecf8427e from 8.8.8.8:443 via http://evil.com/x, " +
+	"mail bad@evil.org, host sub.test.org, mac 00:1a:2b:3c:4d:5e, path C:\\Temp\\x.exe"
+
+func TestContextualizer_ExtractInto(t *testing.T) {
+	c := NewContextualizer()
+	r := NewResult()
+
+	c.ExtractInto(benchText, r)
+	want := c.ExtractAll(benchText)
+	for typ, matches := range want {
+		if len(r.Matches[typ]) != len(matches) {
+			t.Errorf("%s: %v, want %v", typ, r.Matches[typ], matches)
+		}
+	}
+
+	c.ExtractInto("only 1.1.1.1 here", r)
+	if r.Len() != 1 || r.Matches["ipv4"][0].Value != "1.1.1.1" {
+		t.Errorf("reused Result = %v", r.Matches)
+	}
+}
+
+func TestResult_Merge(t *testing.T) {
+	c := NewContextualizer()
+	shard := func(source, text string) *Result {
+		r := NewResult()
+		c.ExtractInto(text, r)
+		r.Source = source
+		return r
+	}
+	r := shard("part-1", "beacon to evil.com from 8.8.8.8")
+	b := shard("part-2", "EVIL.com again and 1.1.1.1")
+	b.Truncated = true
+	b.Suppressed["ipv4"] = 2
+
+	r.Merge(b, shard("part-3", "8.8.8.8"))
+	sources := make(map[string][]string)
+	for _, m := range r.Ordered() {
+		sources[m.Value] = m.Sources
+	}
+	want := map[string][]string{
+		"evil.com": {"part-1", "part-2"},
+		"8.8.8.8":  {"part-1", "part-3"},
+		"1.1.1.1":  {"part-2"},
+	}
+	if !reflect.DeepEqual(sources, want) {
+		t.Errorf("Sources = %v, want %v", sources, want)
+	}
+	if r.Matches["domain"][0].Start != len("beacon to ") {
+		t.Errorf("domain = %+v, want the first occurrence", r.Matches["domain"][0])
+	}
+	if !r.Truncated || r.Suppressed["ipv4"] != 2 {
+		t.Errorf("Truncated = %v, Suppressed = %v", r.Truncated, r.Suppressed)
+	}
+
+	// Merging a merged Result keeps the sources it recorded.
+	total := &Result{Source: "doc"}
+	total.Merge(r)
+	for _, m := range total.Matches["ipv4"] {
+		if m.Value == "8.8.8.8" && !reflect.DeepEqual(m.Sources, []string{"part-1", "part-3"}) {
+			t.Errorf("nested Sources = %v", m.Sources)
+		}
+	}
+	if got := b.Matches["domain"][0].Sources; got != nil {
+		t.Errorf("Merge changed its argument: %v", got)
+	}
+}
+
+func BenchmarkExtractAll(b *testing.B) {
+	c := NewContextualizer()
+	b.ReportAllocs()
+	for b.Loop() {
+		c.ExtractAll(benchText)
+	}
+}
+
+func BenchmarkExtractInto(b *testing.B) {
+	c := NewContextualizer()
+	r := NewResult()
+	b.ReportAllocs()
+	for b.Loop() {
+		c.ExtractInto(benchText, r)
+	}
+}
+
+func TestContextualizer_ExtractOrdered(t *testing.T) {
+	c := NewContextualizer(WithWorkers(4))
+	text := "8.8.8.8 then www.evil.com, d41d8cd98f00b204e9800998ecf8427e and 1.1.1.1"
+
+	first := c.ExtractOrdered(text)
+	for range 20 {
+		if got := c.ExtractOrdered(text); !reflect.DeepEqual(got, first) {
+			t.Fatalf("ExtractOrdered() is not stable:\n%v\n%v", got, first)
+		}
+	}
+	var got []string
+	for _, m := range first {
+		got = append(got, m.Type+":"+m.Value)
+	}
+	want := []string{"ipv4:8.8.8.8", "base_domain:evil.com", "domain:www.evil.com", "md5:d41d8cd98f00b204e9800998ecf8427e", "ipv4:1.1.1.1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractOrdered() = %v, want %v", got, want)
+	}
+
+	r := NewResult()
+	c.ExtractInto(text, r)
+	if !reflect.DeepEqual(r.Ordered(), first) {
+		t.Errorf("Result.Ordered() = %v", r.Ordered())
+	}
+}
@@ -0,0 +1,51 @@
+package parser
+
+import "strings"
+
+// contextRule re-types a match when one of its keywords appears shortly
+// before it on the same line.
+type contextRule struct {
+	Keywords []string
+	Type     string
+}
+
+// contextWindow is how many bytes before a match are searched for keywords.
+const contextWindow = 32
+
+// contextTypes holds the rules per kind. Rules are tried in order, so more
+// specific keywords ("ja3s") must precede their prefixes ("ja3").
+var contextTypes = map[string][]contextRule{
+	"md5": {
+		{Keywords: []string{"ja3s"}, Type: "ja3s"},
+		{Keywords: []string{"ja3"}, Type: "ja3"},
+	},
+}
+
+// retype returns the type a match of kind starting at text[start] should be
+// reported as, based on the keywords preceding it.
+func retype(kind, text string, start int) string {
+	rules, ok := contextTypes[kind]
+	if !ok {
+		return kind
+	}
+
+	lead := leadText(text, start)
+	for _, rule := range rules {
+		for _, kw := range rule.Keywords {
+			if strings.Contains(lead, kw) {
+				return rule.Type
+			}
+		}
+	}
+	return kind
+}
+
+// leadText returns the lowercased text of the contextWindow bytes before
+// text[start], cut at the start of its line.
+func leadText(text string, start int) string {
+	lead := text[max(0, start-contextWindow):start]
+	if nl := strings.LastIndexByte(lead, '\n'); nl != -1 {
+		lead = lead[nl+1:]
+	}
+	return strings.ToLower(lead)
+}
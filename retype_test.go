@@ -0,0 +1,26 @@
+package parser
+
+import "testing"
+
+func TestContextualizer_Fingerprints(t *testing.T) {
+	c := NewContextualizer()
+	text := "JA3: e7d705a3286e19ea42f587b344ee6865\n" +
+		"ja3s=ae4edc6faf64d08308082ad26be60767\n" +
+		"payload md5 d41d8cd98f00b204e9800998ecf8427e\n" +
+		"JA4 t13d1516h2_8daaf6152771_e5627efa2ab1\n" +
+		"JARM 07d14d16d21d21d07c42d41d00041d24a458a375eef0c576d23a7bab9a9fb1"
+
+	results := c.ExtractAll(text)
+
+	for typ, want := range map[string]string{
+		"ja3":  "e7d705a3286e19ea42f587b344ee6865",
+		"ja3s": "ae4edc6faf64d08308082ad26be60767",
+		"md5":  "d41d8cd98f00b204e9800998ecf8427e",
+		"ja4":  "t13d1516h2_8daaf6152771_e5627efa2ab1",
+		"jarm": "07d14d16d21d21d07c42d41d00041d24a458a375eef0c576d23a7bab9a9fb1",
+	} {
+		if len(results[typ]) != 1 || results[typ][0].Value != want {
+			t.Errorf("%s extraction failed: %v", typ, results[typ])
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package parser
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// DefaultRiskWeights are the weights Risk uses when given none: the
+// chance, between 0 and 1, that a single confident match of the kind on
+// its own makes a document dangerous. Kinds missing from the map weigh
+// defaultRiskWeight.
+var DefaultRiskWeights = map[string]float64{
+	"url":                     0.15,
+	"domain":                  0.1,
+	"ipv4":                    0.1,
+	"ipv6":                    0.1,
+	"ipport":                  0.15,
+	"obfuscated_ipv4":         0.4,
+	"email":                   0.05,
+	"md5":                     0.15,
+	"sha1":                    0.15,
+	"sha256":                  0.2,
+	"sha512":                  0.2,
+	"btc":                     0.2,
+	"eth":                     0.2,
+	"jwt":                     0.3,
+	"pem":                     0.3,
+	"ssh_key":                 0.2,
+	"aws_access_key":          0.4,
+	"aws_secret_key":          0.5,
+	"gcp_api_key":             0.4,
+	"github_token":            0.4,
+	"gitlab_token":            0.4,
+	"slack_token":             0.4,
+	"slack_webhook":           0.3,
+	"discord_webhook":         0.3,
+	"azure_connection_string": 0.5,
+	"credit_card":             0.3,
+	"iban":                    0.2,
+	"ssn":                     0.3,
+	"unc":                     0.1,
+	"winpath":                 0.05,
+	"filepath":                0.02,
+	"filename":                0.02,
+	"mac":                     0,
+	"ja4":                     0.1,
+	"jarm":                    0.1,
+	"git_commit":              0,
+	"timestamp":               0,
+}
+
+const defaultRiskWeight = 0.05
+
+// Risk is the document-level risk of a Result.
+type Risk struct {
+	// Score is between 0 and 100.
+	Score float64
+	// Factors break Score down by type, highest first.
+	Factors []RiskFactor
+}
+
+// RiskFactor is what the matches of one type add to a Risk.
+type RiskFactor struct {
+	Type    string
+	Matches int
+	// Malicious and Suspicious count the matches a reputation source
+	// flagged (see ReputationEnricher).
+	Malicious  int
+	Suspicious int
+	// Score is the risk the matches of this type carry on their own,
+	// between 0 and 100.
+	Score float64
+}
+
+// Risk aggregates the matches of r into a single score for thresholding,
+// weighting each type by weights, or DefaultRiskWeights if nil. Each match
+// counts as an independent chance of danger: its type's weight, scaled by
+// its Confidence when scored, raised to 0.9 when a reputation verdict says
+// "malicious" and to at least 0.4 for "suspicious", and zeroed for
+// "harmless". Matches derived from others are not counted again.
+func (r *Result) Risk(weights map[string]float64) Risk {
+	if weights == nil {
+		weights = DefaultRiskWeights
+	}
+	var risk Risk
+	safe := 1.0
+	for typ, matches := range r.Matches {
+		f := RiskFactor{Type: typ}
+		typeSafe := 1.0
+		for _, m := range matches {
+			if m.Parent != "" {
+				continue
+			}
+			f.Matches++
+			p, ok := weights[typ]
+			if !ok {
+				p = defaultRiskWeight
+			}
+			if m.Confidence > 0 {
+				p *= m.Confidence
+			}
+			switch matchVerdict(m) {
+			case "malicious":
+				f.Malicious++
+				p = 0.9
+			case "suspicious":
+				f.Suspicious++
+				p = max(p, 0.4)
+			case "harmless":
+				p = 0
+			}
+			typeSafe *= 1 - min(1, p)
+		}
+		if f.Matches == 0 {
+			continue
+		}
+		safe *= typeSafe
+		f.Score = 100 * (1 - typeSafe)
+		risk.Factors = append(risk.Factors, f)
+	}
+	risk.Score = 100 * (1 - safe)
+	slices.SortFunc(risk.Factors, func(a, b RiskFactor) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.Type, b.Type))
+	})
+	return risk
+}
+
+// matchVerdict returns the most severe reputation verdict attached to m.
+func matchVerdict(m Match) string {
+	var verdict string
+	for key, val := range m.Metadata {
+		if !strings.HasSuffix(key, ".verdict") {
+			continue
+		}
+		switch val = strings.ToLower(val); {
+		case val == "malicious":
+			return val
+		case val == "suspicious", val == "harmless" && verdict == "":
+			verdict = val
+		}
+	}
+	return verdict
+}
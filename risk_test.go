@@ -0,0 +1,53 @@
+package parser
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestResult_Risk(t *testing.T) {
+	r := NewResult()
+	r.Matches["domain"] = []Match{
+		{Value: "evil.example", Type: "domain", Metadata: map[string]string{"vt.verdict": "malicious"}},
+		{Value: "fine.example", Type: "domain", Metadata: map[string]string{"vt.verdict": "harmless"}},
+		{Value: "example", Type: "domain", Parent: "evil.example"},
+	}
+	r.Matches["md5"] = []Match{{Value: "d41d8cd98f00b204e9800998ecf8427e", Type: "md5", Confidence: 0.5}}
+
+	risk := r.Risk(nil)
+	want := 100 * (1 - 0.1*(1-0.15*0.5))
+	if math.Abs(risk.Score-want) > 1e-9 {
+		t.Errorf("Score = %v, want %v", risk.Score, want)
+	}
+	if len(risk.Factors) != 2 {
+		t.Fatalf("Factors = %+v, want 2", risk.Factors)
+	}
+	if f := risk.Factors[0]; f.Type != "domain" || f.Matches != 2 || f.Malicious != 1 || math.Abs(f.Score-90) > 1e-9 {
+		t.Errorf("Factors[0] = %+v, want domain with 2 matches, 1 malicious, score 90", f)
+	}
+	if f := risk.Factors[1]; f.Type != "md5" || math.Abs(f.Score-7.5) > 1e-9 {
+		t.Errorf("Factors[1] = %+v, want md5 scoring 7.5", f)
+	}
+
+	if got := r.Risk(map[string]float64{"domain": 0, "md5": 0}).Score; math.Abs(got-90) > 1e-9 {
+		t.Errorf("Score with zero weights = %v, want 90 from the verdict", got)
+	}
+	if got := NewResult().Risk(nil); got.Score != 0 || got.Factors != nil {
+		t.Errorf("empty Risk = %+v", got)
+	}
+}
+
+func TestResult_RiskFromReputation(t *testing.T) {
+	intel := fakeReputation{"domain:evil.example": {Label: "Suspicious", Score: 40}}
+	c := NewContextualizer(WithEnricher(NewReputationEnricher("vt", intel), 0))
+	r := NewResult()
+	c.ExtractInto("evil.example", r)
+	if err := c.Enrich(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+	risk := r.Risk(nil)
+	if len(risk.Factors) != 1 || risk.Factors[0].Suspicious != 1 || math.Abs(risk.Score-40) > 1e-9 {
+		t.Errorf("Risk = %+v, want one suspicious domain scoring 40", risk)
+	}
+}
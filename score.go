@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// baseConfidence is the starting score per type. Types that only survive a
+// checksum or parser validation start high; loose patterns start low.
+var baseConfidence = map[string]float64{
+	"url":             0.9,
+	"email":           0.9,
+	"ipv4":            0.9,
+	"ipv6":            0.9,
+	"ipport":          0.9,
+	"obfuscated_ipv4": 0.8,
+	"timestamp":       0.9,
+	"mac":             0.8,
+	"btc":             0.95,
+	"eth":             0.8,
+	"jwt":             0.95,
+	"pem":             0.95,
+	"ssh_key":         0.95,
+	"credit_card":     0.9,
+	"iban":            0.9,
+	"ssn":             0.7,
+	"unc":             0.8,
+	"winpath":         0.7,
+	"ja3":             0.8,
+	"ja3s":            0.8,
+	"ja4":             0.9,
+	"jarm":            0.7,
+	"md5":             0.6,
+	"sha1":            0.6,
+	"git_commit":      0.8,
+	"sha256":          0.7,
+	"sha512":          0.7,
+	"domain":          0.5,
+	"base_domain":     0.5,
+	"filepath":        0.3,
+	"filename":        0.3,
+	"port":            0.5,
+	"hostname":        0.4,
+
+	"secret_candidate": 0.4,
+}
+
+// confidenceKeywords boost a match of the type when one of them appears in
+// the text just before it.
+var confidenceKeywords = map[string][]string{
+	"md5":    {"md5", "hash", "checksum"},
+	"sha1":   {"sha1", "sha-1", "hash", "checksum"},
+	"sha256": {"sha256", "sha-256", "hash", "checksum"},
+	"sha512": {"sha512", "sha-512", "hash", "checksum"},
+	"domain": {"domain", "host", "c2", "resolve"},
+	"ipv4":   {"ip", "c2", "connect"},
+}
+
+// confidence scores a match of the given type between 0 and 1. lead is the
+// text immediately preceding the match.
+func (c *Contextualizer) confidence(typ, val, lead string) float64 {
+	score, ok := baseConfidence[typ]
+	if !ok {
+		score = 0.5
+	}
+
+	lead = strings.ToLower(lead)
+	for _, kw := range confidenceKeywords[typ] {
+		if strings.Contains(lead, kw) {
+			score += 0.3
+			break
+		}
+	}
+
+	switch typ {
+	case "domain", "base_domain":
+		// A suffix outside the ICANN section, as in "config.yaml", usually
+		// means a filename rather than a domain.
+		if _, icann := publicsuffix.PublicSuffix(strings.ToLower(val)); icann {
+			score += 0.2
+		} else {
+			score -= 0.3
+		}
+	case "eth":
+		if strings.ToLower(val) != val && validEIP55(val) {
+			score += 0.15
+		}
+	case "md5", "sha1", "sha256", "sha512":
+		if strings.Trim(val, "0123456789") == "" {
+			score -= 0.4
+		}
+	}
+	return min(1, max(0, score))
+}
@@ -0,0 +1,32 @@
+package parser
+
+import "testing"
+
+func TestContextualizer_Confidence(t *testing.T) {
+	// Without the filename heuristic config.yaml is scored as a domain.
+	c := NewContextualizer(WithConfidence(), WithFileExtensions())
+	text := "sha256: e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855 " +
+		"other 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08 " +
+		"visit example.com, open config.yaml"
+
+	results := c.ExtractAll(text)
+
+	confidence := func(kind, value string) float64 {
+		for _, m := range results[kind] {
+			if m.Value == value {
+				return m.Confidence
+			}
+		}
+		t.Fatalf("%s %q not extracted: %v", kind, value, results[kind])
+		return 0
+	}
+
+	hinted := confidence("sha256", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+	plain := confidence("sha256", "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08")
+	if hinted <= plain {
+		t.Errorf("keyword did not boost confidence: %v <= %v", hinted, plain)
+	}
+	if real, fake := confidence("domain", "example.com"), confidence("domain", "config.yaml"); real <= fake {
+		t.Errorf("TLD validity did not affect confidence: %v <= %v", real, fake)
+	}
+}
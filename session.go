@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"strings"
+	"sync"
+)
+
+// SeenStore persists the set of indicators an ExtractionSession has
+// already reported. Implementations must be safe for concurrent use.
+type SeenStore interface {
+	// Add records key and reports whether it was not recorded before.
+	Add(key string) (added bool, err error)
+}
+
+// MemoryStore is a SeenStore kept in memory.
+type MemoryStore struct {
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{seen: make(map[string]struct{})}
+}
+
+// Add implements SeenStore.
+func (s *MemoryStore) Add(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.seen[key]; ok {
+		return false, nil
+	}
+	s.seen[key] = struct{}{}
+	return true, nil
+}
+
+// Len returns the number of recorded keys.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.seen)
+}
+
+// ExtractionSession remembers the indicators found across many documents,
+// so a feed consumer only gets the ones it has not seen before.
+type ExtractionSession struct {
+	c     *Contextualizer
+	store SeenStore
+}
+
+// NewSession starts an ExtractionSession backed by store, or by a new
+// MemoryStore when store is nil.
+func (c *Contextualizer) NewSession(store SeenStore) *ExtractionSession {
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	return &ExtractionSession{c: c, store: store}
+}
+
+// ExtractAll is Contextualizer.ExtractAll limited to indicators that no
+// earlier call in the session returned. Indicators are told apart by type
+// and case-insensitive value. If the store fails, the matches recorded so
+// far are returned along with the error.
+func (s *ExtractionSession) ExtractAll(text string) (map[string][]Match, error) {
+	results := make(map[string][]Match)
+	for typ, matches := range s.c.ExtractAll(text) {
+		for _, m := range matches {
+			added, err := s.store.Add(sessionKey(m))
+			if err != nil {
+				return results, err
+			}
+			if added {
+				results[typ] = append(results[typ], m)
+			}
+		}
+	}
+	return results, nil
+}
+
+func sessionKey(m Match) string {
+	val := m.Normalized
+	if val == "" {
+		val = strings.ToLower(m.Value)
+	}
+	return m.Type + "\x00" + val
+}
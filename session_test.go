@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExtractionSession(t *testing.T) {
+	store := NewMemoryStore()
+	s := NewContextualizer().NewSession(store)
+
+	first, err := s.ExtractAll("beacon to evil.com from 8.8.8.8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first["domain"]) != 1 || len(first["ipv4"]) != 1 {
+		t.Errorf("first = %v", first)
+	}
+
+	second, err := s.ExtractAll("again EVIL.com from 8.8.8.8, now also 1.1.1.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second["domain"] != nil || len(second["ipv4"]) != 1 || second["ipv4"][0].Value != "1.1.1.1" {
+		t.Errorf("second = %v", second)
+	}
+	if store.Len() != 3 {
+		t.Errorf("store.Len() = %d, want 3", store.Len())
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Add(string) (bool, error) { return false, errors.New("disk full") }
+
+func TestExtractionSession_StoreError(t *testing.T) {
+	s := NewContextualizer().NewSession(failingStore{})
+	if _, err := s.ExtractAll("evil.com"); err == nil {
+		t.Error("ExtractAll() ignored the store error")
+	}
+}
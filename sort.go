@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// SortKey selects the order of Result.Sorted.
+type SortKey int
+
+const (
+	// ByPosition orders matches by offset in the document, then by type.
+	ByPosition SortKey = iota
+	// ByValue orders matches by value, then by type and position.
+	ByValue
+	// ByKind orders matches by type, then by position.
+	ByKind
+)
+
+// Sorted returns all matches of r as a single slice ordered by key.
+func (r *Result) Sorted(key SortKey) []Match {
+	matches := orderMatches(r.Matches)
+	switch key {
+	case ByValue:
+		slices.SortStableFunc(matches, func(a, b Match) int {
+			return cmp.Or(cmp.Compare(a.Value, b.Value), cmp.Compare(a.Type, b.Type))
+		})
+	case ByKind:
+		slices.SortStableFunc(matches, func(a, b Match) int {
+			return cmp.Compare(a.Type, b.Type)
+		})
+	}
+	return matches
+}
+
+// GroupByBaseDomain groups the matches that name a host, namely domains,
+// base domains, emails, URLs and UNC paths, by the registrable domain of
+// that host. Matches without a domain, such as IP addresses and hashes,
+// are left out. Each group is in document order.
+func (r *Result) GroupByBaseDomain() map[string][]Match {
+	groups := make(map[string][]Match)
+	for _, m := range r.Sorted(ByPosition) {
+		host := matchHost(m)
+		if host == "" || hostKind(host) != "domain" {
+			continue
+		}
+		base, err := extractSecondLevelDomain(host)
+		if err != nil {
+			base = host
+		}
+		groups[base] = append(groups[base], m)
+	}
+	return groups
+}
+
+// matchHost returns the lowercase host named by a match, or "".
+func matchHost(m Match) string {
+	val := m.Normalized
+	if val == "" {
+		val = m.Value
+	}
+	val = Refang(val)
+	switch m.Type {
+	case "domain", "base_domain":
+		return strings.ToLower(val)
+	case "email":
+		if _, domain, ok := strings.Cut(val, "@"); ok {
+			return strings.ToLower(domain)
+		}
+	case "url":
+		return urlHost(val)
+	case "unc":
+		return strings.ToLower(uncHost(val))
+	}
+	return ""
+}
@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func values(matches []Match) []string {
+	var out []string
+	for _, m := range matches {
+		out = append(out, m.Type+":"+m.Value)
+	}
+	return out
+}
+
+func TestResult_Sorted(t *testing.T) {
+	c := NewContextualizer()
+	r := NewResult()
+	c.ExtractInto("9.9.9.9 and zeta.com then 1.1.1.1 and alpha.com", r)
+
+	tests := []struct {
+		key  SortKey
+		want []string
+	}{
+		{ByPosition, []string{"ipv4:9.9.9.9", "domain:zeta.com", "ipv4:1.1.1.1", "domain:alpha.com"}},
+		{ByValue, []string{"ipv4:1.1.1.1", "ipv4:9.9.9.9", "domain:alpha.com", "domain:zeta.com"}},
+		{ByKind, []string{"domain:zeta.com", "domain:alpha.com", "ipv4:9.9.9.9", "ipv4:1.1.1.1"}},
+	}
+	for _, tt := range tests {
+		if got := values(r.Sorted(tt.key)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Sorted(%d) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestResult_GroupByBaseDomain(t *testing.T) {
+	c := NewContextualizer()
+	r := NewResult()
+	c.ExtractInto("C2 http://cdn.evil.com/x, mail ops@evil.com, host www.bbc.co.uk, ip 8.8.8.8", r)
+
+	groups := r.GroupByBaseDomain()
+	if got, want := values(groups["evil.com"]), []string{"url:http://cdn.evil.com/x", "email:ops@evil.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("evil.com = %v, want %v", got, want)
+	}
+	if got, want := values(groups["bbc.co.uk"]), []string{"base_domain:bbc.co.uk", "domain:www.bbc.co.uk"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("bbc.co.uk = %v, want %v", got, want)
+	}
+	if len(groups) != 2 {
+		t.Errorf("groups = %v", groups)
+	}
+}
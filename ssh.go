@@ -0,0 +1,33 @@
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+)
+
+// parseSSHKey splits an authorized_keys style line into its key type,
+// comment and OpenSSH SHA256 fingerprint. It reports false when the key
+// blob does not decode or names a different type than the line.
+func parseSSHKey(line string) (keyType, comment, fingerprint string, ok bool) {
+	fields := strings.SplitN(strings.TrimSpace(line), " ", 3)
+	if len(fields) < 2 {
+		return "", "", "", false
+	}
+
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil || len(blob) < 4 {
+		return "", "", "", false
+	}
+	n := binary.BigEndian.Uint32(blob)
+	if uint64(n) > uint64(len(blob)-4) || string(blob[4:4+n]) != fields[0] {
+		return "", "", "", false
+	}
+
+	if len(fields) == 3 {
+		comment = strings.TrimSpace(fields[2])
+	}
+	sum := sha256.Sum256(blob)
+	return fields[0], comment, "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:]), true
+}
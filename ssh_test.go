@@ -0,0 +1,24 @@
+package parser
+
+import "testing"
+
+func TestContextualizer_SSHKey(t *testing.T) {
+	const key = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl attacker@kali"
+	c := NewContextualizer()
+	text := "Appended to authorized_keys:\n" + key + "\nssh-rsa AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl mislabeled"
+
+	got := c.GetMatches(text, "ssh_key", c.Expressions["ssh_key"])
+	if len(got) != 1 || got[0].Value != key {
+		t.Fatalf("GetMatches() = %v", got)
+	}
+	want := map[string]string{
+		"key_type":    "ssh-ed25519",
+		"comment":     "attacker@kali",
+		"fingerprint": "SHA256:+DiY3wvvV6TuJJhbpZisF/zLDA0zPMSvHdkr4UvCOqU",
+	}
+	for k, v := range want {
+		if got[0].Metadata[k] != v {
+			t.Errorf("Metadata[%q] = %q, want %q", k, got[0].Metadata[k], v)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package parser
+
+import "time"
+
+// Stats describes a single extraction, for monitoring which expressions
+// are slow or noisy.
+type Stats struct {
+	// Counts is the number of matches per type, including derived ones.
+	Counts map[string]int
+	// Total is the number of matches across all types.
+	Total int
+	// BytesScanned is the size of the input that was scanned, which is
+	// less than its full size when Limits.MaxInputSize cut it.
+	BytesScanned int
+	// Timings is the time spent per expression kind, including the
+	// validation of its candidates. "secret_candidate" is the entropy
+	// detector.
+	Timings map[string]time.Duration
+	// Duration is the time the whole extraction took.
+	Duration time.Duration
+	// Suppressed is the number of values per type dropped by the ignore
+	// lists in PrivateChecks, counting every occurrence.
+	Suppressed map[string]int
+	// Truncated reports that Limits cut the extraction short.
+	Truncated bool
+}
+
+// ExtractAllWithStats is ExtractAll that also reports Stats. The kinds are
+// always scanned one after another, even when Workers is set, so that the
+// timings are comparable.
+func (c *Contextualizer) ExtractAllWithStats(text string) (map[string][]Match, Stats) {
+	stats := Stats{
+		Counts:     make(map[string]int),
+		Timings:    make(map[string]time.Duration),
+		Suppressed: make(map[string]int),
+	}
+	results := make(map[string][]Match)
+	start := time.Now()
+	stats.Truncated = c.scanStats(text, c.expressions(), c.Entropy, 0, &stats, func(m Match) bool {
+		results[m.Type] = append(results[m.Type], m)
+		stats.Counts[m.Type]++
+		stats.Total++
+		return true
+	})
+	stats.Duration = time.Since(start)
+	return results, stats
+}
+
+// time starts timing kind and returns the function that stops it. It does
+// nothing when s records no Timings.
+func (s *Stats) time(kind string) func() {
+	if s == nil || s.Timings == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		s.Timings[kind] += time.Since(start)
+	}
+}
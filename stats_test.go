@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContextualizer_ExtractAllWithStats(t *testing.T) {
+	c := NewContextualizer()
+
+	got, stats := c.ExtractAllWithStats(benchText)
+	if want := c.ExtractAll(benchText); !reflect.DeepEqual(got, want) {
+		t.Errorf("matches = %v, want %v", got, want)
+	}
+
+	total := 0
+	for typ, matches := range got {
+		if stats.Counts[typ] != len(matches) {
+			t.Errorf("Counts[%s] = %d, want %d", typ, stats.Counts[typ], len(matches))
+		}
+		total += len(matches)
+	}
+	if stats.Total != total {
+		t.Errorf("Total = %d, want %d", stats.Total, total)
+	}
+	if stats.BytesScanned != len(benchText) {
+		t.Errorf("BytesScanned = %d, want %d", stats.BytesScanned, len(benchText))
+	}
+	for _, kind := range []string{"url", "ipv4", "md5"} {
+		if _, ok := stats.Timings[kind]; !ok {
+			t.Errorf("no timing for %s", kind)
+		}
+	}
+	if stats.Duration <= 0 {
+		t.Errorf("Duration = %v", stats.Duration)
+	}
+}
+
+func TestContextualizer_ExtractAllWithStats_Truncated(t *testing.T) {
+	c := NewContextualizer(WithLimits(Limits{MaxInputSize: 20}))
+
+	_, stats := c.ExtractAllWithStats(benchText)
+	if stats.BytesScanned != 20 || !stats.Truncated {
+		t.Errorf("BytesScanned = %d, Truncated = %v", stats.BytesScanned, stats.Truncated)
+	}
+}
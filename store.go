@@ -0,0 +1,157 @@
+package parser
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sighting is what a Store knows about one indicator across the documents
+// it was found in.
+type Sighting struct {
+	Type string `json:"type"`
+	// Value is the indicator as it was first recorded.
+	Value string `json:"value"`
+	// FirstSeen and LastSeen are the times of the earliest and latest
+	// recording.
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	// Hits counts the times the indicator was recorded.
+	Hits int `json:"hits"`
+	// Documents are the IDs of the documents the indicator was found in,
+	// in the order they were first recorded.
+	Documents []string `json:"documents"`
+}
+
+// Store correlates indicators across many extractions. Indicators are told
+// apart by type and normalized, case-insensitive value. Implementations
+// must be safe for concurrent use.
+type Store interface {
+	// Record adds matches found in document doc at time at.
+	Record(doc string, at time.Time, matches []Match) error
+	// Lookup returns the sighting of the indicator of the given kind and
+	// value, and whether there is one.
+	Lookup(kind, value string) (Sighting, bool, error)
+	// Sightings returns the sightings of every indicator of the given kind,
+	// or of all kinds when kind is empty, sorted by type and value.
+	Sightings(kind string) ([]Sighting, error)
+}
+
+// MemoryIndicatorStore is a Store kept in memory.
+type MemoryIndicatorStore struct {
+	mu        sync.Mutex
+	sightings map[string]*Sighting
+}
+
+// NewMemoryIndicatorStore returns an empty MemoryIndicatorStore.
+func NewMemoryIndicatorStore() *MemoryIndicatorStore {
+	return &MemoryIndicatorStore{sightings: make(map[string]*Sighting)}
+}
+
+// Record implements Store.
+func (s *MemoryIndicatorStore) Record(doc string, at time.Time, matches []Match) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, m := range matches {
+		s.add(newSighting(doc, at, m))
+	}
+	return nil
+}
+
+// add merges in with the sighting of the same indicator, if any.
+func (s *MemoryIndicatorStore) add(in Sighting) {
+	key := indicatorKey(in.Type, in.Value)
+	if si, ok := s.sightings[key]; ok {
+		si.merge(in)
+		return
+	}
+	si := in.clone()
+	s.sightings[key] = &si
+}
+
+// Lookup implements Store.
+func (s *MemoryIndicatorStore) Lookup(kind, value string) (Sighting, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	si, ok := s.sightings[indicatorKey(kind, value)]
+	if !ok {
+		return Sighting{}, false, nil
+	}
+	return si.clone(), true, nil
+}
+
+// Sightings implements Store.
+func (s *MemoryIndicatorStore) Sightings(kind string) ([]Sighting, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Sighting
+	for _, si := range s.sightings {
+		if kind == "" || si.Type == kind {
+			out = append(out, si.clone())
+		}
+	}
+	sortSightings(out)
+	return out, nil
+}
+
+// Track extracts the indicators of text, records them in s as found in
+// document doc now, and returns them. doc is the Source of the extraction,
+// so the matches carry its SourceTags.
+func (c *Contextualizer) Track(s Store, doc, text string) (map[string][]Match, error) {
+	r := NewResult()
+	r.Source = doc
+	c.ExtractInto(text, r)
+	return r.Matches, s.Record(doc, time.Now(), r.Ordered())
+}
+
+// newSighting is the sighting of a single recording of m.
+func newSighting(doc string, at time.Time, m Match) Sighting {
+	return Sighting{
+		Type:      m.Type,
+		Value:     m.Value,
+		FirstSeen: at,
+		LastSeen:  at,
+		Hits:      1,
+		Documents: []string{doc},
+	}
+}
+
+// merge adds the recordings of o, a sighting of the same indicator.
+func (si *Sighting) merge(o Sighting) {
+	si.Hits += o.Hits
+	if o.FirstSeen.Before(si.FirstSeen) {
+		si.FirstSeen = o.FirstSeen
+	}
+	if o.LastSeen.After(si.LastSeen) {
+		si.LastSeen = o.LastSeen
+	}
+	for _, doc := range o.Documents {
+		if !slices.Contains(si.Documents, doc) {
+			si.Documents = append(si.Documents, doc)
+		}
+	}
+}
+
+func (si *Sighting) clone() Sighting {
+	out := *si
+	out.Documents = slices.Clone(si.Documents)
+	return out
+}
+
+func sortSightings(sightings []Sighting) {
+	slices.SortFunc(sightings, func(a, b Sighting) int {
+		return cmp.Or(cmp.Compare(a.Type, b.Type), cmp.Compare(a.Value, b.Value))
+	})
+}
+
+// indicatorKey identifies an indicator in a Store.
+func indicatorKey(kind, value string) string {
+	return kind + "\x00" + valueKey(kind, value)
+}
+
+// valueKey identifies an indicator among those of its kind.
+func valueKey(kind, value string) string {
+	return strings.ToLower(Normalize(kind, value))
+}
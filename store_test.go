@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryIndicatorStore(t *testing.T) {
+	s := NewMemoryIndicatorStore()
+	c := NewContextualizer()
+	t0 := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	docs := []struct {
+		id, text string
+		at       time.Time
+	}{
+		{"msg-2", "beacon to EVIL.com from 8.8.8.8", t0.Add(time.Hour)},
+		{"msg-1", "first seen evil.com", t0},
+		{"msg-2", "evil.com again", t0.Add(2 * time.Hour)},
+	}
+	for _, d := range docs {
+		if err := s.Record(d.id, d.at, c.ExtractOrdered(d.text)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, ok, err := s.Lookup("domain", "Evil.COM")
+	if err != nil || !ok {
+		t.Fatalf("Lookup() = %v, %v", ok, err)
+	}
+	want := Sighting{
+		Type:      "domain",
+		Value:     "EVIL.com",
+		FirstSeen: t0,
+		LastSeen:  t0.Add(2 * time.Hour),
+		Hits:      3,
+		Documents: []string{"msg-2", "msg-1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Lookup() = %+v, want %+v", got, want)
+	}
+	got.Documents[0] = "changed"
+	if again, _, _ := s.Lookup("domain", "evil.com"); again.Documents[0] != "msg-2" {
+		t.Error("Lookup() returned the store's own Documents")
+	}
+
+	if _, ok, _ := s.Lookup("ipv4", "1.1.1.1"); ok {
+		t.Error("Lookup() found an unrecorded indicator")
+	}
+	ips, _ := s.Sightings("ipv4")
+	if len(ips) != 1 || ips[0].Value != "8.8.8.8" || ips[0].Hits != 1 {
+		t.Errorf("Sightings(ipv4) = %+v", ips)
+	}
+	all, _ := s.Sightings("")
+	if len(all) != 2 || all[0].Type != "domain" || all[1].Type != "ipv4" {
+		t.Errorf("Sightings() = %+v", all)
+	}
+}
+
+func TestContextualizer_Track(t *testing.T) {
+	s := NewMemoryIndicatorStore()
+	c := NewContextualizer()
+
+	var wg sync.WaitGroup
+	for _, doc := range []string{"a", "b", "c", "d"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Track(s, doc, "callback to 8.8.8.8"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	got, ok, _ := s.Lookup("ipv4", "8.8.8.8")
+	if !ok || got.Hits != 4 || len(got.Documents) != 4 || got.FirstSeen.After(got.LastSeen) {
+		t.Errorf("Lookup() = %+v", got)
+	}
+}
@@ -0,0 +1,33 @@
+package parser
+
+import "context"
+
+// ExtractStream scans text in the background and sends every match on the
+// returned channel as soon as it is found. Both channels are closed when the
+// scan finishes; if ctx is cancelled first, its error is sent on the error
+// channel and the scan stops. If c.Limits cut the scan short, ErrTruncated
+// is sent instead.
+func (c *Contextualizer) ExtractStream(ctx context.Context, text string) (<-chan Match, <-chan error) {
+	matches := make(chan Match)
+	errc := make(chan error, 1)
+	exprs := c.expressions()
+
+	go func() {
+		defer close(errc)
+		defer close(matches)
+		truncated := c.scan(text, exprs, c.Entropy, 0, func(m Match) bool {
+			select {
+			case matches <- m:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+		if err := ctx.Err(); err != nil {
+			errc <- err
+		} else if truncated {
+			errc <- ErrTruncated
+		}
+	}()
+	return matches, errc
+}
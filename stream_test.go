@@ -0,0 +1,38 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestContextualizer_ExtractStream(t *testing.T) {
+	c := NewContextualizer()
+	text := "Hash d41d8cd98f00b204e9800998ecf8427e from 8.8.8.8 via http://evil.com/x"
+
+	matches, errc := c.ExtractStream(context.Background(), text)
+	got := make(map[string]int)
+	for m := range matches {
+		got[m.Type]++
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("ExtractStream() error = %v", err)
+	}
+	if got["md5"] != 1 || got["ipv4"] != 1 || got["url"] != 1 {
+		t.Errorf("ExtractStream() types = %v", got)
+	}
+}
+
+func TestContextualizer_ExtractStreamCancel(t *testing.T) {
+	c := NewContextualizer()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	matches, errc := c.ExtractStream(ctx, "8.8.8.8 1.1.1.1 9.9.9.9 http://evil.com/x")
+	<-matches
+	cancel()
+	for range matches {
+	}
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("ExtractStream() error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+)
+
+// Summary is the triage overview of a Result.
+type Summary struct {
+	// Total is the number of matches across all types.
+	Total int
+	// Unique is the number of distinct values per type.
+	Unique map[string]int
+	// Top lists the most frequent values per type, most frequent first.
+	Top map[string][]ValueCount
+	// Suppressed is the number of values per type dropped by the ignore
+	// lists.
+	Suppressed map[string]int
+	// TLP is the marking of the Result (see Result.Marking).
+	TLP TLP
+}
+
+// ValueCount is a value and the number of times it occurs.
+type ValueCount struct {
+	Value string
+	Count int
+}
+
+// Summary counts the matches of r, keeping the n most frequent values per
+// type in Top. Values only repeat when r was filled with NoDedup; ties are
+// broken by value. With n <= 0 Top holds empty lists, for callers that
+// only want the counts.
+func (r *Result) Summary(n int) Summary {
+	n = max(n, 0)
+	s := Summary{
+		Unique:     make(map[string]int, len(r.Matches)),
+		Top:        make(map[string][]ValueCount, len(r.Matches)),
+		Suppressed: maps.Clone(r.Suppressed),
+		TLP:        r.Marking(),
+	}
+	if s.Suppressed == nil {
+		s.Suppressed = make(map[string]int)
+	}
+	for typ, matches := range r.Matches {
+		if len(matches) == 0 {
+			continue
+		}
+		counts := make(map[string]int)
+		for _, m := range matches {
+			counts[cmp.Or(m.Normalized, m.Value)]++
+		}
+		s.Total += len(matches)
+		s.Unique[typ] = len(counts)
+
+		top := make([]ValueCount, 0, len(counts))
+		for val, count := range counts {
+			top = append(top, ValueCount{Value: val, Count: count})
+		}
+		slices.SortFunc(top, func(a, b ValueCount) int {
+			return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Value, b.Value))
+		})
+		s.Top[typ] = top[:min(n, len(top))]
+	}
+	return s
+}
@@ -0,0 +1,36 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResult_Summary(t *testing.T) {
+	c := NewContextualizer(WithIgnoredDomains("example.com"))
+	r := NewResult()
+
+	text := "hit 1.1.1.1, 8.8.8.8 and 1.1.1.1 again; see example.com and a.example.com, then evil.org"
+	c.ExtractIntoFlags(text, r, NoDedup)
+	s := r.Summary(1)
+
+	if s.Unique["ipv4"] != 2 || s.Unique["domain"] != 1 {
+		t.Errorf("Unique = %v", s.Unique)
+	}
+	if want := []ValueCount{{"1.1.1.1", 2}}; !reflect.DeepEqual(s.Top["ipv4"], want) {
+		t.Errorf("Top[ipv4] = %v, want %v", s.Top["ipv4"], want)
+	}
+	if s.Suppressed["domain"] != 2 {
+		t.Errorf("Suppressed = %v", s.Suppressed)
+	}
+	if s.Total != r.Len() {
+		t.Errorf("Total = %d, want %d", s.Total, r.Len())
+	}
+	if s := r.Summary(-1); len(s.Top["ipv4"]) != 0 || s.Unique["ipv4"] != 2 {
+		t.Errorf("Summary(-1): Top = %v, Unique = %v", s.Top, s.Unique)
+	}
+
+	c.ExtractIntoFlags(text, r, NoIgnore)
+	if s := r.Summary(5); len(s.Suppressed) != 0 {
+		t.Errorf("Suppressed under NoIgnore = %v", s.Suppressed)
+	}
+}
@@ -0,0 +1,34 @@
+package parser
+
+import "slices"
+
+// Tagger returns labels for a match, such as "phishing" for urls on a
+// lookalike domain. It returns nil to add none.
+type Tagger func(m Match) []string
+
+// tag adds the tags of c.Taggers to m.
+func (c *Contextualizer) tag(m Match) Match {
+	for _, t := range c.Taggers {
+		m.Tags = appendUnique(m.Tags, t(m)...)
+	}
+	return m
+}
+
+// sourceTags returns the tags configured for source. Reload swaps
+// c.SourceTags, so extractions read them once through here.
+func (c *Contextualizer) sourceTags(source string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.SourceTags[source]
+}
+
+func cloneSourceTags(tags map[string][]string) map[string][]string {
+	if tags == nil {
+		return nil
+	}
+	clone := make(map[string][]string, len(tags))
+	for source, list := range tags {
+		clone[source] = slices.Clone(list)
+	}
+	return clone
+}
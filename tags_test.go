@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestContextualizer_Taggers(t *testing.T) {
+	c := NewContextualizer(
+		WithClassification("domain", Classification{Tags: []string{"network"}}),
+		WithKindTags("domain", "network", "dns"),
+		WithKindTags("ipv4", "network"),
+		WithTagger(func(m Match) []string {
+			if m.Type == "domain" && strings.Contains(m.Value, "paypal") {
+				return []string{"phishing", "dns"}
+			}
+			return nil
+		}),
+		WithSourceTags("gateway", "email", "network"),
+	)
+
+	r := &Result{Source: "gateway"}
+	c.ExtractInto("login at paypal-verify.example and ok.example from 8.8.8.8", r)
+	tags := make(map[string][]string)
+	for _, m := range r.Ordered() {
+		tags[m.Value] = m.Tags
+	}
+	want := map[string][]string{
+		"paypal-verify.example": {"network", "dns", "phishing", "email"},
+		"ok.example":            {"network", "dns", "email"},
+		"8.8.8.8":               {"network", "email"},
+	}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("Tags = %v, want %v", tags, want)
+	}
+
+	// Without a Source, only the kind and tagger labels apply, and the
+	// shared classification tags are left alone.
+	got := c.ExtractAll("paypal-verify.example")["domain"]
+	if len(got) != 1 || !reflect.DeepEqual(got[0].Tags, []string{"network", "dns", "phishing"}) {
+		t.Errorf("domain = %+v", got)
+	}
+	if cl := c.Classifications["domain"]; !reflect.DeepEqual(cl.Tags, []string{"network", "dns"}) {
+		t.Errorf("Classification tags = %v", cl.Tags)
+	}
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timestampLayouts are the RFC 3339 variants seen in logs: with a 'T' or a
+// space between date and time, and with or without a zone. Values without
+// a zone are taken as UTC.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// formatTimestamp is the canonical form of a parsed timestamp: RFC 3339 in
+// UTC, so values from different sources sort and bucket together.
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// parseTimestamp parses a timestamp match and reports its format:
+// "rfc3339", "syslog" or "epoch". Syslog timestamps carry no year and are
+// placed in the most recent year that doesn't put them in the future;
+// epoch values of 13 digits are milliseconds.
+func parseTimestamp(val string) (time.Time, string, bool) {
+	switch {
+	case strings.ContainsRune(val, '-'):
+		val = strings.Replace(strings.Replace(val, "t", "T", 1), "z", "Z", 1)
+		for _, layout := range timestampLayouts {
+			if t, err := time.Parse(layout, val); err == nil {
+				return t, "rfc3339", true
+			}
+		}
+	case strings.ContainsRune(val, ':'):
+		t, err := time.Parse(time.Stamp, val)
+		if err != nil {
+			return time.Time{}, "", false
+		}
+		now := time.Now().UTC()
+		t = t.AddDate(now.Year(), 0, 0)
+		if t.After(now.Add(24 * time.Hour)) {
+			t = t.AddDate(-1, 0, 0)
+		}
+		return t, "syslog", true
+	default:
+		n, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return time.Time{}, "", false
+		}
+		if len(val) == 13 {
+			return time.UnixMilli(n).UTC(), "epoch", true
+		}
+		return time.Unix(n, 0).UTC(), "epoch", true
+	}
+	return time.Time{}, "", false
+}
@@ -0,0 +1,38 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContextualizer_Timestamps(t *testing.T) {
+	c := NewContextualizer(WithNormalization())
+	if err := c.EnableProfile("timestamps"); err != nil {
+		t.Fatal(err)
+	}
+	text := "2024-03-05T10:20:30.5+02:00 login ok\n" +
+		"2024-03-05 08:20:31 retry from 192.0.2.1\n" +
+		`{"ts": 1709634032, "created_at": "1709634033000", "id": 1709634034}` + "\n" +
+		"version 2024-13-45T99:00:00Z is not a time"
+
+	var got []string
+	for _, m := range c.ExtractAll(text)["timestamp"] {
+		got = append(got, m.Normalized+" "+m.Metadata["format"])
+	}
+	want := []string{
+		"2024-03-05T08:20:30.5Z rfc3339",
+		"2024-03-05T08:20:31Z rfc3339",
+		"2024-03-05T10:20:32Z epoch",
+		"2024-03-05T10:20:33Z epoch",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("timestamps = %q, want %q", got, want)
+	}
+}
+
+func TestParseTimestampSyslog(t *testing.T) {
+	ts, format, ok := parseTimestamp("Mar  5 10:20:30")
+	if !ok || format != "syslog" || ts.Month() != 3 || ts.Day() != 5 || ts.Hour() != 10 || ts.Year() < 2024 {
+		t.Errorf("parseTimestamp() = %v, %q, %v", ts, format, ok)
+	}
+}
@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"container/list"
+	"sync"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// tldCacheSize bounds the number of registrable-domain lookups remembered.
+const tldCacheSize = 4096
+
+// tldCache remembers EffectiveTLDPlusOne results, since the same domains
+// tend to repeat across a corpus thousands of times.
+var tldCache = newLRU[tldEntry](tldCacheSize)
+
+// tldEntry is the cached result of EffectiveTLDPlusOne for a domain.
+type tldEntry struct {
+	base string
+	err  error
+}
+
+// lru is a mutex-guarded least-recently-used cache of V values by key.
+type lru[V any] struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List // of lruItem[V], front is most recently used
+	items map[string]*list.Element
+}
+
+type lruItem[V any] struct {
+	key   string
+	value V
+}
+
+func newLRU[V any](size int) *lru[V] {
+	return &lru[V]{size: size, order: list.New(), items: make(map[string]*list.Element, size)}
+}
+
+func (l *lru[V]) get(key string) (V, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	el, ok := l.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	l.order.MoveToFront(el)
+	return el.Value.(lruItem[V]).value, true
+}
+
+func (l *lru[V]) put(key string, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	item := lruItem[V]{key: key, value: value}
+	if el, ok := l.items[key]; ok {
+		el.Value = item
+		l.order.MoveToFront(el)
+		return
+	}
+	l.items[key] = l.order.PushFront(item)
+	if l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.items, oldest.Value.(lruItem[V]).key)
+	}
+}
+
+func (l *lru[V]) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+// extractSecondLevelDomain returns the registrable domain (eTLD+1) of
+// domain, consulting tldCache first.
+func extractSecondLevelDomain(domain string) (string, error) {
+	if e, ok := tldCache.get(domain); ok {
+		return e.base, e.err
+	}
+	base, err := publicsuffix.EffectiveTLDPlusOne(domain)
+	tldCache.put(domain, tldEntry{base: base, err: err})
+	return base, err
+}
@@ -0,0 +1,49 @@
+package parser
+
+import "testing"
+
+func TestLRU_Evicts(t *testing.T) {
+	l := newLRU[tldEntry](2)
+	l.put("a.example.com", tldEntry{base: "example.com"})
+	l.put("b.example.com", tldEntry{base: "example.com"})
+	l.get("a.example.com")
+	l.put("c.example.com", tldEntry{base: "example.com"})
+
+	if l.len() != 2 {
+		t.Fatalf("len = %d, want 2", l.len())
+	}
+	if _, ok := l.get("b.example.com"); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	if e, ok := l.get("a.example.com"); !ok || e.base != "example.com" {
+		t.Errorf("get(a.example.com) = %v, %v", e, ok)
+	}
+}
+
+func TestExtractSecondLevelDomain_Cached(t *testing.T) {
+	for range 2 {
+		base, err := extractSecondLevelDomain("www.bbc.co.uk")
+		if err != nil || base != "bbc.co.uk" {
+			t.Errorf("extractSecondLevelDomain() = %q, %v", base, err)
+		}
+	}
+	if _, ok := tldCache.get("www.bbc.co.uk"); !ok {
+		t.Error("lookup was not cached")
+	}
+	if _, err := extractSecondLevelDomain("co.uk"); err == nil {
+		t.Error("public suffix has no registrable domain")
+	}
+}
+
+func TestContextualizer_BaseDomainFastPath(t *testing.T) {
+	c := NewContextualizer()
+	if base, ok := c.baseDomain(c.Checks, "example.com"); ok {
+		t.Errorf("baseDomain(example.com) = %q, want none", base)
+	}
+	if _, ok := tldCache.get("example.com"); ok {
+		t.Error("single-dot name went through the lookup")
+	}
+	if base, ok := c.baseDomain(c.Checks, "a.b.example.com"); !ok || base != "example.com" {
+		t.Errorf("baseDomain(a.b.example.com) = %q, %v", base, ok)
+	}
+}
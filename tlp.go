@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TLP is a Traffic Light Protocol 2.0 marking, restricting who matches may
+// be shared with. The zero TLP means unmarked.
+type TLP string
+
+const (
+	TLPClear       TLP = "CLEAR"
+	TLPGreen       TLP = "GREEN"
+	TLPAmber       TLP = "AMBER"
+	TLPAmberStrict TLP = "AMBER+STRICT"
+	TLPRed         TLP = "RED"
+)
+
+// tlpRank orders markings from the least to the most restrictive.
+var tlpRank = map[TLP]int{
+	TLPClear:       1,
+	TLPGreen:       2,
+	TLPAmber:       3,
+	TLPAmberStrict: 4,
+	TLPRed:         5,
+}
+
+// ParseTLP parses a marking such as "TLP:AMBER" or "amber". The TLP 1.0
+// WHITE is read as CLEAR, and an empty string as unmarked.
+func ParseTLP(s string) (TLP, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	t := TLP(strings.TrimPrefix(s, "TLP:"))
+	if t == "WHITE" {
+		t = TLPClear
+	}
+	if _, ok := tlpRank[t]; !ok && t != "" {
+		return "", fmt.Errorf("invalid TLP %q", s)
+	}
+	return t, nil
+}
+
+// String returns the marking as written on documents, e.g. "TLP:AMBER", or
+// "" when unmarked.
+func (t TLP) String() string {
+	if t == "" {
+		return ""
+	}
+	return "TLP:" + string(t)
+}
+
+// stricterTLP returns the more restrictive of a and b.
+func stricterTLP(a, b TLP) TLP {
+	if tlpRank[b] > tlpRank[a] {
+		return b
+	}
+	return a
+}
+
+// Marking returns the most restrictive of r.TLP and the TLP of its
+// matches, which is the marking a document built from r must carry.
+func (r *Result) Marking() TLP {
+	t := r.TLP
+	for _, matches := range r.Matches {
+		for _, m := range matches {
+			t = stricterTLP(t, m.TLP)
+		}
+	}
+	return t
+}
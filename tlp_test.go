@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTLP(t *testing.T) {
+	tests := []struct {
+		in   string
+		want TLP
+	}{
+		{"TLP:AMBER", TLPAmber},
+		{" amber+strict ", TLPAmberStrict},
+		{"tlp:white", TLPClear},
+		{"RED", TLPRed},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got, err := ParseTLP(tt.in); err != nil || got != tt.want {
+			t.Errorf("ParseTLP(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := ParseTLP("TLP:PURPLE"); err == nil {
+		t.Error("ParseTLP accepted TLP:PURPLE")
+	}
+	if TLPGreen.String() != "TLP:GREEN" || TLP("").String() != "" {
+		t.Errorf("String() = %q, %q", TLPGreen.String(), TLP("").String())
+	}
+}
+
+func TestContextualizer_TLP(t *testing.T) {
+	c := NewContextualizer(WithTLP(TLPGreen))
+	for _, m := range c.ExtractOrdered("evil.com at 8.8.8.8") {
+		if m.TLP != TLPGreen {
+			t.Errorf("%s TLP = %q, want GREEN", m.Value, m.TLP)
+		}
+	}
+
+	// A Result marks its own extraction.
+	r := &Result{TLP: TLPRed}
+	c.ExtractInto("evil.com", r)
+	if m := r.Matches["domain"]; len(m) != 1 || m[0].TLP != TLPRed {
+		t.Errorf("domain = %+v, want TLP RED", m)
+	}
+
+	// Merged Results keep the most restrictive marking.
+	green := NewResult()
+	c.ExtractInto("8.8.8.8", green)
+	amber := &Result{TLP: TLPAmber}
+	c.ExtractInto("1.1.1.1", amber)
+	green.Merge(amber)
+	if green.TLP != TLPAmber || green.Marking() != TLPAmber {
+		t.Errorf("merged TLP = %q, Marking() = %q", green.TLP, green.Marking())
+	}
+	if got := green.Summary(0).TLP; got != TLPAmber {
+		t.Errorf("Summary().TLP = %q", got)
+	}
+	if dot := green.ToDOT(); !strings.Contains(dot, "\tlabel=\"TLP:AMBER\";\n") {
+		t.Errorf("ToDOT() missing the marking:\n%s", dot)
+	}
+
+	if err := c.ApplyConfig(Config{TLP: "bogus"}); err == nil {
+		t.Error("ApplyConfig accepted an invalid TLP")
+	}
+	if c.Config().TLP != TLPGreen {
+		t.Errorf("Config().TLP = %q", c.Config().TLP)
+	}
+}
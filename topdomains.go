@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LoadTopDomains reads a domain popularity list such as Tranco or the
+// Alexa top 1M, one "rank,domain" or bare domain per line, and ignores the
+// first n entries (all of them when n <= 0). It returns the number of
+// entries read.
+//
+// Unlike IgnoredDomains, a top domain only covers hosts whose registrable
+// domain it is, so listing a shared hosting suffix such as github.io does
+// not hide every site hosted under it.
+func (c *Contextualizer) LoadTopDomains(r io.Reader, n int) (int, error) {
+	var domains []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() && (n <= 0 || len(domains) < n) {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, domain, ok := strings.Cut(line, ","); ok {
+			line = domain
+		}
+		domains = append(domains, strings.ToLower(strings.TrimSpace(line)))
+	}
+	if err := sc.Err(); err != nil {
+		return 0, fmt.Errorf("reading top domains: %w", err)
+	}
+	c.updateChecks(func(p *PrivateChecks) {
+		if p.TopDomains == nil {
+			p.TopDomains = make(map[string]struct{}, len(domains))
+		}
+		for _, d := range domains {
+			p.TopDomains[d] = struct{}{}
+		}
+	})
+	return len(domains), nil
+}
+
+// topDomain reports whether domain, or the registrable domain it belongs
+// to, is one of TopDomains.
+func (p *PrivateChecks) topDomain(domain string) bool {
+	if len(p.TopDomains) == 0 {
+		return false
+	}
+	if _, ok := p.TopDomains[domain]; ok {
+		return true
+	}
+	base, err := extractSecondLevelDomain(domain)
+	if err != nil {
+		return false
+	}
+	_, ok := p.TopDomains[base]
+	return ok
+}
@@ -0,0 +1,32 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContextualizer_TopDomainAllowlist(t *testing.T) {
+	tranco := "1,google.com\n2,github.io\n3,Microsoft.com\n4,evil.com\n"
+	c := NewContextualizer(WithTopDomainAllowlist(strings.NewReader(tranco), 3))
+
+	got := c.ExtractAll("www.google.com github.io attacker.github.io login.microsoft.com evil.com " +
+		"mail admin@google.com")
+	var domains []string
+	for _, m := range got["domain"] {
+		domains = append(domains, m.Value)
+	}
+	if strings.Join(domains, " ") != "attacker.github.io evil.com" {
+		t.Errorf("domain = %v", domains)
+	}
+	if got["email"] != nil {
+		t.Errorf("email = %v", got["email"])
+	}
+
+	n, err := c.LoadTopDomains(strings.NewReader("# header\nevil.com\n"), 0)
+	if err != nil || n != 1 {
+		t.Fatalf("LoadTopDomains() = %d, %v", n, err)
+	}
+	if got := c.ExtractAll("evil.com")["domain"]; got != nil {
+		t.Errorf("domain = %v", got)
+	}
+}
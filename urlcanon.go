@@ -0,0 +1,183 @@
+package parser
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// trackingParams are query parameters that only identify a campaign or a
+// click, and are dropped by CanonicalURL along with every utm_* parameter.
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"dclid":   true,
+	"msclkid": true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"igshid":  true,
+}
+
+// defaultPorts are the ports implied by a URL scheme.
+var defaultPorts = map[string]string{
+	"http":  ":80",
+	"https": ":443",
+	"ftp":   ":21",
+}
+
+// CanonicalURL rewrites a URL into a canonical form, so the same link
+// written several ways compares equal: the scheme and host are lowercased,
+// the scheme's default port is removed, percent-encoded unreserved
+// characters are decoded and the remaining escapes uppercased, tracking
+// parameters such as utm_source and fbclid are stripped, and the remaining
+// query parameters are sorted. Values that are not URLs are returned
+// unchanged.
+func CanonicalURL(val string) string {
+	val = normalizeURL(val)
+	scheme, rest, ok := strings.Cut(val, "://")
+	if !ok {
+		return val
+	}
+	end := strings.IndexAny(rest, "/?#")
+	if end < 0 {
+		end = len(rest)
+	}
+	authority, tail := rest[:end], rest[end:]
+	authority = strings.TrimSuffix(authority, defaultPorts[scheme])
+
+	tail, fragment, hasFragment := strings.Cut(tail, "#")
+	path, query, _ := strings.Cut(tail, "?")
+
+	var b strings.Builder
+	b.Grow(len(val))
+	b.WriteString(scheme)
+	b.WriteString("://")
+	b.WriteString(authority)
+	b.WriteString(unescapeUnreserved(path))
+	if query = canonicalQuery(query); query != "" {
+		b.WriteByte('?')
+		b.WriteString(query)
+	}
+	if hasFragment {
+		b.WriteByte('#')
+		b.WriteString(unescapeUnreserved(fragment))
+	}
+	return b.String()
+}
+
+// equivalentURL reduces val to the form it shares with its http and https,
+// default-port and trailing-slash variants.
+func equivalentURL(val string) string {
+	scheme, rest, ok := strings.Cut(normalizeURL(val), "://")
+	if !ok {
+		return val
+	}
+	end := strings.IndexAny(rest, "/?#")
+	if end < 0 {
+		end = len(rest)
+	}
+	authority, tail := rest[:end], rest[end:]
+	authority = strings.TrimSuffix(authority, defaultPorts[scheme])
+	if scheme == "https" {
+		scheme = "http"
+	}
+	path, suffix := tail, ""
+	if i := strings.IndexAny(tail, "?#"); i >= 0 {
+		path, suffix = tail[:i], tail[i:]
+	}
+	return scheme + "://" + authority + strings.TrimRight(path, "/") + suffix
+}
+
+// addAlias records val as another form of m, unless it is m's own.
+func addAlias(m Match, val string) Match {
+	if val != m.Value && !slices.Contains(m.Aliases, val) {
+		m.Aliases = append(m.Aliases, val)
+	}
+	return m
+}
+
+// canonicalQuery drops tracking and empty parameters from a raw query and
+// sorts the rest, keeping the order of repeated keys.
+func canonicalQuery(query string) string {
+	var params []string
+	for param := range strings.SplitSeq(query, "&") {
+		if param == "" {
+			continue
+		}
+		key, _, _ := strings.Cut(param, "=")
+		if name, err := url.QueryUnescape(key); err == nil && isTrackingParam(strings.ToLower(name)) {
+			continue
+		}
+		params = append(params, unescapeUnreserved(param))
+	}
+	slices.SortStableFunc(params, func(a, b string) int {
+		ka, _, _ := strings.Cut(a, "=")
+		kb, _, _ := strings.Cut(b, "=")
+		return strings.Compare(ka, kb)
+	})
+	return strings.Join(params, "&")
+}
+
+func isTrackingParam(name string) bool {
+	return strings.HasPrefix(name, "utm_") || trackingParams[name]
+}
+
+// unescapeUnreserved decodes percent escapes of unreserved characters
+// (letters, digits and "-._~"), which mean the same either way, and
+// uppercases the hex digits of the others.
+func unescapeUnreserved(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' || i+2 >= len(s) || !isHexByte(s[i+1]) || !isHexByte(s[i+2]) {
+			b.WriteByte(s[i])
+			continue
+		}
+		ch := unhex(s[i+1])<<4 | unhex(s[i+2])
+		if isWordByte(ch) || ch == '-' || ch == '.' || ch == '~' {
+			b.WriteByte(ch)
+		} else {
+			b.WriteByte('%')
+			b.WriteString(strings.ToUpper(s[i+1 : i+3]))
+		}
+		i += 2
+	}
+	return b.String()
+}
+
+func unhex(c byte) byte {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0'
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
+
+// urlComponents splits a URL into the parts attached to url matches by
+// DecomposeURLs. Empty parts are left out.
+func urlComponents(val string) map[string]string {
+	u, err := url.Parse(Refang(val))
+	if err != nil {
+		return nil
+	}
+	parts := map[string]string{
+		"scheme":   strings.ToLower(u.Scheme),
+		"host":     strings.ToLower(u.Hostname()),
+		"port":     u.Port(),
+		"path":     u.EscapedPath(),
+		"query":    u.RawQuery,
+		"fragment": u.EscapedFragment(),
+	}
+	for k, v := range parts {
+		if v == "" {
+			delete(parts, k)
+		}
+	}
+	return parts
+}
@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCanonicalURL(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"HTTPS://Example.COM:443/a?b=2&a=1", "https://example.com/a?a=1&b=2"},
+		{"http://example.com:8080/", "http://example.com:8080/"},
+		{"http://example.com/?utm_source=news&utm_medium=mail&id=7&fbclid=abc", "http://example.com/?id=7"},
+		{"http://example.com/%7euser/%2fx?q=%41%2b", "http://example.com/~user/%2Fx?q=A%2B"},
+		{"http://example.com/a?x=2&x=1#Top", "http://example.com/a?x=2&x=1#Top"},
+		{"not a url", "not a url"},
+	}
+	for _, tt := range tests {
+		if got := CanonicalURL(tt.in); got != tt.want {
+			t.Errorf("CanonicalURL(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestContextualizer_CanonicalURLs(t *testing.T) {
+	text := "read http://example.com/post?utm_source=a&id=1 or http://Example.com:80/post?id=1&fbclid=x"
+
+	c := NewContextualizer(WithCanonicalURLs())
+	got := c.ExtractAll(text)["url"]
+	if len(got) != 1 || got[0].Value != "http://example.com/post?id=1" {
+		t.Fatalf("url = %+v", got)
+	}
+	if got[0].End != len("read http://example.com/post?utm_source=a&id=1") {
+		t.Errorf("End = %d, want offset of the original text", got[0].End)
+	}
+
+	if got := NewContextualizer().ExtractAll(text)["url"]; len(got) != 2 {
+		t.Errorf("url without CanonicalURLs = %+v", got)
+	}
+}
+
+func TestContextualizer_DecomposeURLs(t *testing.T) {
+	c := NewContextualizer(WithURLDecomposition())
+
+	got := c.ExtractAll("fetch https://Evil.com:8443/a/b?x=1#frag and http://10.0.0.1/c")
+	urls := got["url"]
+	if len(urls) != 2 {
+		t.Fatalf("url = %+v", urls)
+	}
+	want := map[string]string{
+		"scheme":   "https",
+		"host":     "evil.com",
+		"port":     "8443",
+		"path":     "/a/b",
+		"query":    "x=1",
+		"fragment": "frag",
+	}
+	if !reflect.DeepEqual(urls[0].Metadata, want) {
+		t.Errorf("Metadata = %v, want %v", urls[0].Metadata, want)
+	}
+	if m := got["domain"]; len(m) != 1 || m[0].Value != "evil.com" || m[0].Parent != urls[0].Value {
+		t.Errorf("domain = %+v", m)
+	}
+	if m := got["ipv4"]; len(m) != 1 || m[0].Value != "10.0.0.1" || m[0].Parent != urls[1].Value {
+		t.Errorf("ipv4 = %+v", m)
+	}
+
+	if got := NewContextualizer().ExtractAll("fetch https://evil.com/a"); got["url"][0].Metadata != nil || len(got["domain"]) != 0 {
+		t.Errorf("without DecomposeURLs = %+v", got)
+	}
+}
+
+func TestEquivalentURL(t *testing.T) {
+	same := []string{
+		"http://example.com/login",
+		"https://example.com/login/",
+		"HTTPS://Example.com:443/login",
+		"http://example.com:80/login//",
+	}
+	want := equivalentURL(same[0])
+	for _, in := range same[1:] {
+		if got := equivalentURL(strings.ToLower(in)); got != want {
+			t.Errorf("equivalentURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+	for _, in := range []string{
+		"http://example.com:8080/login",
+		"ftp://example.com/login",
+		"http://example.com/login?next=/",
+	} {
+		if got := equivalentURL(in); got == want {
+			t.Errorf("equivalentURL(%q) = %q, want it distinct", in, got)
+		}
+	}
+}
+
+func TestContextualizer_EquivalentURLs(t *testing.T) {
+	text := "see http://example.com/a?q=1, https://example.com/a/?q=1 and https://example.com:443/a?q=1 or http://example.com:8080/a?q=1"
+
+	c := NewContextualizer(WithEquivalentURLs())
+	got := c.ExtractAll(text)["url"]
+	if len(got) != 2 {
+		t.Fatalf("url = %+v", got)
+	}
+	if got[0].Value != "http://example.com/a?q=1" || got[0].Start != len("see ") {
+		t.Errorf("url[0] = %+v", got[0])
+	}
+	if want := []string{"https://example.com/a/?q=1", "https://example.com:443/a?q=1"}; !reflect.DeepEqual(got[0].Aliases, want) {
+		t.Errorf("Aliases = %q, want %q", got[0].Aliases, want)
+	}
+	if got[1].Value != "http://example.com:8080/a?q=1" || got[1].Aliases != nil {
+		t.Errorf("url[1] = %+v", got[1])
+	}
+
+	if got := c.GetMatches(text, "url", c.expressions()["url"]); len(got) != 2 || len(got[0].Aliases) != 2 {
+		t.Errorf("GetMatches = %+v", got)
+	}
+	if got := NewContextualizer().ExtractAll(text)["url"]; len(got) != 4 {
+		t.Errorf("url without EquivalentURLs = %+v", got)
+	}
+}
@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// DefaultURLSchemes are the schemes the built-in url expression matches.
+var DefaultURLSchemes = []string{"http", "https", "ftp"}
+
+// urlExpression returns a url expression for schemes. Every scheme but
+// mailto is matched in its scheme:// form, with an optional third slash
+// for file:///paths; mailto: links are matched up to the next whitespace
+// and scanned by scanMailto.
+func urlExpression(schemes []string) *regexp.Regexp {
+	var hier []string
+	var mailto bool
+	for _, s := range schemes {
+		s = strings.ToLower(strings.TrimSuffix(strings.TrimSuffix(s, "://"), ":"))
+		if s == "mailto" {
+			mailto = true
+			continue
+		}
+		hier = append(hier, regexp.QuoteMeta(s))
+	}
+	var alts []string
+	if len(hier) > 0 {
+		alts = append(alts, `\b(?:`+strings.Join(hier, "|")+`):\/\/\/?[^\s/$.?#].[^\s]*`)
+	}
+	if mailto {
+		alts = append(alts, `\bmailto:[^\s@]+@[^\s]+`)
+	}
+	if len(alts) == 0 {
+		// Matches nothing.
+		return regexp.MustCompile(`(?i)(\b\B)`)
+	}
+	return regexp.MustCompile(`(?i)(` + strings.Join(alts, "|") + `)`)
+}
+
+// isMailto reports whether the url candidate val is a mailto: link.
+func isMailto(val string) bool {
+	return len(val) > len("mailto:") && strings.EqualFold(val[:len("mailto:")], "mailto:")
+}
+
+// scanMailto emits the recipients of the mailto: link val, found at
+// src.text[start:], as email matches and then claims the link, so the
+// email expression doesn't report them a second time. Their keys are kept
+// in res.mailto for the email scan to skip repeats elsewhere in the text.
+// It reports whether yield wants more.
+func (c *Contextualizer) scanMailto(src source, val string, start int, res *resolver, yield func(Match) bool) bool {
+	if res.mailto == nil {
+		res.mailto = make(map[string]bool)
+	}
+	seen := res.mailto
+	to, _, _ := strings.Cut(val[len("mailto:"):], "?")
+	off := start + len("mailto:")
+	for _, addr := range strings.Split(to, ",") {
+		if rcpt, err := url.PathUnescape(addr); err == nil && strings.Contains(rcpt, "@") {
+			if !c.consider(src, "email", canonicalize("email", rcpt), off, off+len(addr), nil, seen, res, yield) {
+				return false
+			}
+		}
+		off += len(addr) + 1
+	}
+	res.claim("url", start, start+len(val))
+	return true
+}
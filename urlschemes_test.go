@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContextualizer_URLSchemes(t *testing.T) {
+	c := NewContextualizer(WithURLSchemes("https", "file", "smb", "ftps", "ws", "wss", "tcp", "ldap", "mailto"))
+	text := "read file:///etc/shadow, mounted smb://fs01.corp.example/share, " +
+		"beacon wss://c2.evil.net/ws and tcp://198.51.100.7:4444, query ldap://dc1.evil.net/dc=evil, " +
+		"contact mailto:Ops@Evil.NET,abuse%40evil.net?subject=hi or https://evil.net/x, skip http://old.example"
+
+	got := c.ExtractAll(text)
+	var urls []string
+	for _, m := range got["url"] {
+		urls = append(urls, m.Value)
+	}
+	want := []string{
+		"file:///etc/shadow",
+		"smb://fs01.corp.example/share",
+		"wss://c2.evil.net/ws",
+		"tcp://198.51.100.7:4444",
+		"ldap://dc1.evil.net/dc=evil",
+		"https://evil.net/x",
+	}
+	if !reflect.DeepEqual(urls, want) {
+		t.Errorf("urls = %q, want %q", urls, want)
+	}
+
+	emails := got["email"]
+	if len(emails) != 2 || emails[0].Value != "Ops@Evil.NET" || emails[1].Value != "abuse@evil.net" {
+		t.Fatalf("email = %v", emails)
+	}
+	if raw := text[emails[1].Start:emails[1].End]; raw != "abuse%40evil.net" {
+		t.Errorf("second recipient span = %q", raw)
+	}
+}
+
+func TestContextualizer_URLSchemeSuffix(t *testing.T) {
+	// ws and https are suffixes of news and xhttps, which aren't enabled.
+	c := NewContextualizer(WithURLSchemes("ws", "https"))
+	got := c.ExtractAll("read news://news.example.com/group, xhttps://a.example/x and ws://c2.evil.net/ws")["url"]
+	if len(got) != 1 || got[0].Value != "ws://c2.evil.net/ws" {
+		t.Errorf("url = %v, want [ws://c2.evil.net/ws]", got)
+	}
+}
+
+func TestContextualizer_MailtoDedup(t *testing.T) {
+	for _, workers := range []int{0, 4} {
+		c := NewContextualizer(WithWorkers(workers), WithURLSchemes("https", "mailto"))
+		got := c.ExtractAll("mailto:a@evil.com?subject=x or a@evil.com")
+		if emails := got["email"]; len(emails) != 1 || emails[0].Value != "a@evil.com" || emails[0].Start != len("mailto:") {
+			t.Errorf("workers %d: email = %+v, want a single a@evil.com from the link", workers, emails)
+		}
+	}
+}
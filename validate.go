@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// Validator reports whether a candidate value is semantically valid, on
+// top of matching its expression.
+type Validator func(value string) bool
+
+// RegisterValidator adds v to the checks every candidate of kind must pass
+// before it is emitted. Validators of a kind run in the order they were
+// registered, after the built-in checks.
+func (c *Contextualizer) RegisterValidator(kind string, v Validator) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.validators == nil {
+		c.validators = make(map[string][]Validator)
+	}
+	c.validators[kind] = append(c.validators[kind], v)
+}
+
+// validate runs the validators registered for kind over val.
+func (c *Contextualizer) validate(kind, val string) bool {
+	c.mu.RLock()
+	validators := c.validators[kind]
+	c.mu.RUnlock()
+	for _, v := range validators {
+		if !v(val) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidDNSName is a Validator for domains that enforces the DNS length
+// limits: at most 253 characters, with labels of 1 to 63 characters that
+// don't start or end with a hyphen.
+func ValidDNSName(name string) bool {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" || len(name) > 253 {
+		return false
+	}
+	for label := range strings.SplitSeq(name, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// KnownTLD is a Validator for domains that requires the name to end in a
+// public suffix from the ICANN section of the Public Suffix List, which
+// rules out "config.yaml" and the like.
+func KnownTLD(name string) bool {
+	suffix, icann := publicsuffix.PublicSuffix(strings.ToLower(strings.TrimSuffix(name, ".")))
+	return icann && suffix != name
+}
@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestContextualizer_RegisterValidator(t *testing.T) {
+	c := NewContextualizer()
+	c.RegisterValidator("domain", KnownTLD)
+	c.RegisterValidator("domain", func(v string) bool { return !strings.HasPrefix(v, "test.") })
+
+	got := c.ExtractAll("load config.yaml, then call evil.com and test.example.org")["domain"]
+	if len(got) != 1 || got[0].Value != "evil.com" {
+		t.Errorf("domain = %+v", got)
+	}
+
+	clone := c.Clone()
+	clone.RegisterValidator("domain", func(string) bool { return false })
+	if got := c.ExtractAll("call evil.com")["domain"]; len(got) != 1 {
+		t.Errorf("validator registered on clone leaked: %+v", got)
+	}
+}
+
+func TestValidDNSName(t *testing.T) {
+	tests := map[string]bool{
+		"example.com":                     true,
+		"a-b.example.com.":                true,
+		"-bad.example.com":                false,
+		"bad-.example.com":                false,
+		"a..example.com":                  false,
+		strings.Repeat("a", 64) + ".com":  false,
+		strings.Repeat("a.", 127) + "com": false,
+	}
+	for name, want := range tests {
+		if got := ValidDNSName(name); got != want {
+			t.Errorf("ValidDNSName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestContextualizer_RequireKnownTLD(t *testing.T) {
+	c := NewContextualizer(WithKnownTLDs())
+	got := c.ExtractAll("edit config.yaml and notes.txt, then call evil.co.uk and bücher.de")
+
+	var domains []string
+	for _, m := range got["domain"] {
+		domains = append(domains, m.Value)
+	}
+	want := []string{"evil.co.uk", "bücher.de"}
+	if !reflect.DeepEqual(domains, want) {
+		t.Errorf("domains = %v, want %v", domains, want)
+	}
+}